@@ -10,10 +10,13 @@ import (
 
 	"github.com/Azure/azqr/internal"
 	"github.com/Azure/azqr/internal/scanners"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	rulesCmd.PersistentFlags().StringArrayP("azqr-rules-dir", "", []string{}, "Load declarative AZQR rules from a directory (can be repeated)")
+	rulesCmd.PersistentFlags().StringArrayP("rules-dir", "", []string{}, "Load custom Resource Graph recommendations (YAML and KQL files) from a directory, as <dir> or <source>=<dir> (can be repeated)")
 	rootCmd.AddCommand(rulesCmd)
 }
 
@@ -23,8 +26,21 @@ var rulesCmd = &cobra.Command{
 	Long:  "Print all recommendations as markdown table",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		azqrRulesDirs, _ := cmd.Flags().GetStringArray("azqr-rules-dir")
+		declarative, err := scanners.LoadDeclarativeRules(azqrRulesDirs)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load rules")
+		}
+
+		rulesDirs, _ := cmd.Flags().GetStringArray("rules-dir")
+		rulePacks, err := internal.LoadRulePacks(rulesDirs)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load rule packs")
+		}
+
 		_, serviceScanners := scanners.GetScanners()
 		aprlScanner := internal.NewAprlScanner(serviceScanners, nil, nil)
+		aprlScanner.AddRulePacks(rulePacks...)
 		aprl := aprlScanner.GetAprlRecommendations()
 
 		// Print count of aprl recommendations
@@ -36,7 +52,7 @@ var rulesCmd = &cobra.Command{
 
 		i := 0
 		for _, scanner := range serviceScanners {
-			rm := scanner.GetRecommendations()
+			rm := scanners.ScannerRecommendations(scanner, declarative)
 
 			recommendations := map[string]scanners.AzqrRecommendation{}
 			for _, r := range rm {
@@ -4,12 +4,23 @@
 package azqr
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/Azure/azqr/internal"
+	"github.com/Azure/azqr/internal/clouds"
 	"github.com/Azure/azqr/internal/scanners"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 
 	"github.com/spf13/cobra"
 )
 
+// exitCodeQualityGate is the exit code of a scan whose findings break a --fail-on rule
+const exitCodeQualityGate = 2
+
 func init() {
 	scanCmd.PersistentFlags().StringP("management-group-id", "", "", "Azure Management Group Id")
 	scanCmd.PersistentFlags().StringP("subscription-id", "s", "", "Azure Subscription Id")
@@ -19,12 +30,39 @@ func init() {
 	scanCmd.PersistentFlags().BoolP("costs", "c", true, "Scan Azure Costs (default)")
 	scanCmd.PersistentFlags().BoolP("json", "", false, "Create json file")
 	scanCmd.PersistentFlags().BoolP("csv", "", false, "Create csv files")
+	scanCmd.PersistentFlags().BoolP("html", "", false, "Create a self-contained html report")
+	scanCmd.PersistentFlags().BoolP("sarif", "", false, "Create a SARIF file for code scanning dashboards")
+	scanCmd.PersistentFlags().BoolP("markdown", "", false, "Create a markdown summary for pull requests and wikis")
+	scanCmd.PersistentFlags().BoolP("junit", "", false, "Create a JUnit XML file with a test case per recommendation")
+	scanCmd.PersistentFlags().StringArrayP("fail-on", "", []string{}, "Exit with code 2 when the new findings break a rule: impact=<impact>, category=<category> or count, with an optional >N threshold (can be repeated)")
+	scanCmd.PersistentFlags().StringP("baseline", "", "", "Findings file (<output name>.findings.json) of a previous scan with --json. Only the findings missing from it are new")
 	scanCmd.PersistentFlags().StringP("output-name", "o", "", "Output file name without extension")
 	scanCmd.PersistentFlags().BoolP("mask", "m", true, "Mask the subscription id in the report (default)")
-	scanCmd.PersistentFlags().BoolP("azure-cli-credential", "f", false, "Force the use of Azure CLI Credential")
+	scanCmd.PersistentFlags().BoolP("azure-cli-credential", "f", false, "Force the use of Azure CLI Credential (same as --auth cli)")
+	scanCmd.PersistentFlags().StringP("auth", "", internal.AuthDefault, fmt.Sprintf("Authentication mode (%s)", strings.Join(internal.AuthModes(), ", ")))
+	scanCmd.PersistentFlags().StringP("client-id", "", "", "Client Id of the managed identity, workload identity, service principal or device code application")
+	scanCmd.PersistentFlags().StringP("tenant-id", "", "", "Azure Tenant Id to authenticate with")
+	scanCmd.PersistentFlags().StringSliceP("tenants", "", []string{}, "Tenant Ids to scan, each with its own credential. The results are merged in a single report")
+	scanCmd.PersistentFlags().StringP("tenants-file", "", "", "File with the Tenant Ids to scan, one per line")
+	scanCmd.PersistentFlags().StringP("client-certificate", "", "", "Path to the PEM or PKCS12 certificate used with --auth client-certificate")
 	scanCmd.PersistentFlags().BoolP("debug", "", false, "Set log level to debug")
 	scanCmd.PersistentFlags().StringP("filters", "e", "", "Filters file (YAML format)")
 	scanCmd.PersistentFlags().BoolP("azqr", "", true, "Scan Azure Quick Review Recommendations (default)")
+	scanCmd.PersistentFlags().IntP("parallelism", "", 1, "Number of subscriptions scanned concurrently")
+	scanCmd.PersistentFlags().IntP("max-concurrent-requests", "", 100, "Maximum number of in-flight ARM requests across all subscriptions")
+	scanCmd.PersistentFlags().StringP("record", "", "", "Record the ARM and Resource Graph traffic to a cassette directory")
+	scanCmd.PersistentFlags().StringP("replay", "", "", "Replay the ARM and Resource Graph traffic from a cassette directory (offline scan)")
+	scanCmd.PersistentFlags().StringP("cloud", "", "AzureCloud", fmt.Sprintf("Azure cloud to scan (%s)", strings.Join(clouds.Names(), ", ")))
+	scanCmd.PersistentFlags().BoolP("checkpoint", "", false, "Save the scan progress to a state file next to the output, to resume an interrupted scan")
+	scanCmd.PersistentFlags().StringP("resume", "", "", "Resume an interrupted scan from its state file")
+	scanCmd.PersistentFlags().StringArrayP("azqr-rules-dir", "", []string{}, "Load declarative AZQR rules from a directory (can be repeated)")
+	scanCmd.PersistentFlags().StringArrayP("rules-dir", "", []string{}, "Load custom Resource Graph recommendations (YAML and KQL files) from a directory, as <dir> or <source>=<dir> (can be repeated)")
+	scanCmd.PersistentFlags().StringP("policy-dir", "", "", "Evaluate the deny rules of the Rego policies of a directory against the resources")
+	scanCmd.PersistentFlags().BoolP("remediation", "", false, "Add the fixes of the findings (az CLI, Bicep or Terraform) to the report and write a remediation script per subscription")
+	scanCmd.PersistentFlags().StringP("workloads", "", "", "Workload definitions (YAML format). The composite SLA of each workload is rendered in the WorkloadSLA sheet")
+	scanCmd.PersistentFlags().StringP("tag-policy", "", "", "Required tags and their allowed values (YAML format), used by the tag rules instead of checking that resources have tags")
+	scanCmd.PersistentFlags().StringP("naming-policy", "", "", "Naming conventions per resource type (YAML format), used by the naming rules instead of the CAF prefixes")
+	scanCmd.PersistentFlags().StringP("since", "", "", "Scan only the resources changed since a RFC3339 timestamp, or since a previous scan given its state file or its findings file (<output name>.findings.json)")
 
 	rootCmd.AddCommand(scanCmd)
 }
@@ -50,14 +88,66 @@ func scan(cmd *cobra.Command, scannerKeys []string) {
 	cost, _ := cmd.Flags().GetBool("costs")
 	csv, _ := cmd.Flags().GetBool("csv")
 	json, _ := cmd.Flags().GetBool("json")
+	html, _ := cmd.Flags().GetBool("html")
+	sarif, _ := cmd.Flags().GetBool("sarif")
+	junit, _ := cmd.Flags().GetBool("junit")
+	markdown, _ := cmd.Flags().GetBool("markdown")
+	failOnValues, _ := cmd.Flags().GetStringArray("fail-on")
+	baseline, _ := cmd.Flags().GetString("baseline")
 	mask, _ := cmd.Flags().GetBool("mask")
 	debug, _ := cmd.Flags().GetBool("debug")
 	forceAzureCliCredential, _ := cmd.Flags().GetBool("azure-cli-credential")
+	auth, _ := cmd.Flags().GetString("auth")
+	clientID, _ := cmd.Flags().GetString("client-id")
+	tenantID, _ := cmd.Flags().GetString("tenant-id")
+	clientCertificate, _ := cmd.Flags().GetString("client-certificate")
+	tenantIDs, _ := cmd.Flags().GetStringSlice("tenants")
+	tenantsFile, _ := cmd.Flags().GetString("tenants-file")
 	filtersFile, _ := cmd.Flags().GetString("filters")
 	useAzqr, _ := cmd.Flags().GetBool("azqr")
+	parallelism, _ := cmd.Flags().GetInt("parallelism")
+	maxConcurrentRequests, _ := cmd.Flags().GetInt("max-concurrent-requests")
+	recordDir, _ := cmd.Flags().GetString("record")
+	replayDir, _ := cmd.Flags().GetString("replay")
+	azureCloud, _ := cmd.Flags().GetString("cloud")
+	checkpoint, _ := cmd.Flags().GetBool("checkpoint")
+	resumeFile, _ := cmd.Flags().GetString("resume")
+	since, _ := cmd.Flags().GetString("since")
+	azqrRulesDirs, _ := cmd.Flags().GetStringArray("azqr-rules-dir")
+	rulesDirs, _ := cmd.Flags().GetStringArray("rules-dir")
+	policyDir, _ := cmd.Flags().GetString("policy-dir")
+	namingPolicy, _ := cmd.Flags().GetString("naming-policy")
+	tagPolicy, _ := cmd.Flags().GetString("tag-policy")
+	workloadsFile, _ := cmd.Flags().GetString("workloads")
+	remediation, _ := cmd.Flags().GetBool("remediation")
+
+	// Default level for this example is info, unless debug flag is present
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	if debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		log.Debug().Msg("Debug logging enabled")
+	}
+
+	// parse the quality gate rules
+	failOn, err := internal.ParseFailOnRules(failOnValues)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse the quality gate rules")
+	}
 
 	// load filters
-	filters := scanners.LoadFilters(filtersFile, scannerKeys)
+	filters, err := scanners.LoadFilters(filtersFile, scannerKeys)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load filters")
+	}
+
+	// load tenants
+	if tenantsFile != "" {
+		tenants, err := internal.LoadTenants(tenantsFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load tenants")
+		}
+		tenantIDs = append(tenantIDs, tenants...)
+	}
 
 	params := internal.ScanParams{
 		ManagementGroupID:       managementGroupID,
@@ -67,16 +157,60 @@ func scan(cmd *cobra.Command, scannerKeys []string) {
 		Defender:                defender,
 		Advisor:                 advisor,
 		Cost:                    cost,
+		Xlsx:                    true,
 		Csv:                     csv,
 		Json:                    json,
+		Html:                    html,
+		Sarif:                   sarif,
+		Junit:                   junit,
+		Markdown:                markdown,
+		Baseline:                baseline,
 		Mask:                    mask,
-		Debug:                   debug,
 		ScannerKeys:             scannerKeys,
 		ForceAzureCliCredential: forceAzureCliCredential,
 		Filters:                 filters,
 		UseAzqrRecommendations:  useAzqr,
+		Parallelism:             parallelism,
+		MaxConcurrentRequests:   maxConcurrentRequests,
+		RecordDir:               recordDir,
+		ReplayDir:               replayDir,
+		Checkpoint:              checkpoint,
+		ResumeFile:              resumeFile,
+		Since:                   since,
+		AzqrRulesDirs:           azqrRulesDirs,
+		RulesDirs:               rulesDirs,
+		PolicyDir:               policyDir,
+		NamingPolicy:            namingPolicy,
+		TagPolicy:               tagPolicy,
+		WorkloadsFile:           workloadsFile,
+		Remediation:             remediation,
+		Cloud:                   azureCloud,
+		ClientID:                clientID,
+		TenantID:                tenantID,
+		ClientCertificate:       clientCertificate,
+		TenantIDs:               tenantIDs,
+	}
+
+	// --azure-cli-credential is kept for compatibility with previous versions
+	if !forceAzureCliCredential || cmd.Flags().Changed("auth") {
+		params.Auth = auth
 	}
 
 	scanner := internal.Scanner{}
-	scanner.Scan(&params)
+	reportData, err := scanner.Scan(context.Background(), &params)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to scan")
+	}
+
+	if err := scanner.Render(reportData, &params); err != nil {
+		log.Fatal().Err(err).Msg("Failed to render reports")
+	}
+
+	// fail the pipeline when the new findings break the quality gate
+	if broken := internal.EvaluateQualityGate(failOn, reportData.NewFindings()); len(broken) > 0 {
+		for _, b := range broken {
+			log.Error().Msgf("Quality gate failed: %s", b)
+		}
+		os.Exit(exitCodeQualityGate)
+	}
 }
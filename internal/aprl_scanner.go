@@ -6,16 +6,19 @@ package internal
 import (
 	"context"
 	"embed"
+	"fmt"
 	"io/fs"
 	"math"
+	"sort"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/Azure/azqr/internal/graph"
 	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azqr/internal/state"
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 )
@@ -34,6 +37,10 @@ type (
 		serviceScanners []scanners.IAzureScanner
 		filters         *scanners.Filters
 		subscriptions   map[string]string
+		// resourceTypes restricts the queried recommendations to the given resource types (lower case). Nil queries all of them
+		resourceTypes map[string]bool
+		// rulePacks are custom recommendations queried with the APRL recommendations
+		rulePacks []RulePack
 	}
 
 	ScanType string
@@ -57,7 +64,12 @@ func NewAprlScanner(serviceScanners []scanners.IAzureScanner, filters *scanners.
 	}
 }
 
-// GetAprlRecommendations returns a map with all APRL recommendations
+// AddRulePacks adds custom recommendations to the APRL recommendations
+func (a *AprlScanner) AddRulePacks(packs ...RulePack) {
+	a.rulePacks = append(a.rulePacks, packs...)
+}
+
+// GetAprlRecommendations returns a map with all APRL recommendations, including the recommendations of the rule packs
 func (a AprlScanner) GetAprlRecommendations() map[string]map[string]scanners.AprlRecommendation {
 	recommendations := map[string]map[string]scanners.AprlRecommendation{}
 	for _, t := range a.scanType {
@@ -76,19 +88,41 @@ func (a AprlScanner) GetAprlRecommendations() map[string]map[string]scanners.Apr
 			}
 		}
 	}
+
+	// rule packs replace the built-in recommendations with the same id
+	for _, p := range a.rulePacks {
+		for t, rs := range p.recommendations {
+			for id, r := range rs {
+				if recommendations[t] == nil {
+					recommendations[t] = map[string]scanners.AprlRecommendation{}
+				}
+				recommendations[t][id] = r
+			}
+		}
+	}
 	return recommendations
 }
 
 func (a AprlScanner) getAprlRecommendations(path string) map[string]map[string]scanners.AprlRecommendation {
-	r := map[string]map[string]scanners.AprlRecommendation{}
-
 	fsys, err := fs.Sub(embededFiles, path)
 	if err != nil {
 		return nil
 	}
 
+	r, err := loadAprlRecommendations(fsys)
+	if err != nil {
+		return nil
+	}
+	return r
+}
+
+// loadAprlRecommendations loads the recommendations of the *.yaml files of a filesystem,
+// with the graph queries of the *.kql files named after the recommendation ids
+func loadAprlRecommendations(fsys fs.FS) (map[string]map[string]scanners.AprlRecommendation, error) {
+	r := map[string]map[string]scanners.AprlRecommendation{}
+
 	q := map[string]string{}
-	err = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -104,7 +138,7 @@ func (a AprlScanner) getAprlRecommendations(path string) map[string]map[string]s
 		return nil
 	})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	err = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
@@ -120,7 +154,7 @@ func (a AprlScanner) getAprlRecommendations(path string) map[string]map[string]s
 			var recommendations []scanners.AprlRecommendation
 			err = yaml.Unmarshal(content, &recommendations)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to parse %s: %w", path, err)
 			}
 
 			for _, recommendation := range recommendations {
@@ -140,105 +174,166 @@ func (a AprlScanner) getAprlRecommendations(path string) map[string]map[string]s
 		return nil
 	})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	return r
+	return r, nil
 }
 
-// AprlScan scans Azure resources using Azure Proactive Resiliency Library v2 (APRL)
-func (a AprlScanner) Scan(ctx context.Context, cred azcore.TokenCredential) (map[string]map[string]scanners.AprlRecommendation, []scanners.AprlResult) {
+// AprlScan scans Azure resources using Azure Proactive Resiliency Library v2 (APRL).
+// Failed queries are returned as scan errors and do not stop the scan.
+// Batches completed in the previous state are not queried again, and each completed batch is saved to the checkpoint.
+func (a AprlScanner) Scan(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, previous *state.State, checkpoint *state.Checkpoint) (map[string]map[string]scanners.AprlRecommendation, []scanners.AprlResult, []scanners.ScanError) {
 	recommendations := map[string]map[string]scanners.AprlRecommendation{}
 	results := []scanners.AprlResult{}
+	scanErrors := []scanners.ScanError{}
 	rules := []scanners.AprlRecommendation{}
-	graph := graph.NewGraphQuery(cred)
+	graph, err := graph.NewGraphQuery(cred, options)
+	if err != nil {
+		scanErrors = append(scanErrors, scanners.NewScanError(scanners.PhaseAprl, "", "", "Resource Graph", err))
+		return recommendations, results, scanErrors
+	}
 
 	// get APRL recommendations
 	aprl := a.GetAprlRecommendations()
 
+	// the resource types of the service scanners, and the resource types of the rule packs without a service scanner
+	types := []string{}
 	for _, s := range a.serviceScanners {
-		for _, t := range s.ResourceTypes() {
-			scanners.LogResourceTypeScan(t)
-			gr := a.getGraphRules(t, aprl)
+		types = append(types, s.ResourceTypes()...)
+	}
+	packTypes := map[string]bool{}
+	for _, t := range a.rulePackTypes() {
+		packTypes[t] = true
+		types = append(types, t)
+	}
+
+	for _, t := range types {
+		scanners.LogResourceTypeScan(t)
+		gr := a.getGraphRules(t, aprl)
+		if a.resourceTypes == nil || a.resourceTypes[strings.ToLower(t)] {
 			for _, r := range gr {
 				rules = append(rules, r)
 			}
+		}
 
-			for i, r := range gr {
-				if recommendations[strings.ToLower(t)] == nil {
-					recommendations[strings.ToLower(t)] = map[string]scanners.AprlRecommendation{}
-				}
-				recommendations[strings.ToLower(t)][i] = r
+		for i, r := range gr {
+			if recommendations[strings.ToLower(t)] == nil {
+				recommendations[strings.ToLower(t)] = map[string]scanners.AprlRecommendation{}
 			}
+			recommendations[strings.ToLower(t)][i] = r
 		}
 	}
 
+	// sort the rules, so the batches are the same when a scan is resumed
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].RecommendationID != rules[j].RecommendationID {
+			return rules[i].RecommendationID < rules[j].RecommendationID
+		}
+		return rules[i].ResourceType < rules[j].ResourceType
+	})
+
 	batches := int(math.Ceil(float64(len(rules)) / 12))
 
-	jobs := make(chan []scanners.AprlRecommendation, batches)
-	ch := make(chan []scanners.AprlResult, batches)
+	jobs := make(chan aprlBatch, batches)
+	batchResults := make([]aprlBatchResult, batches)
 	var wg sync.WaitGroup
 
 	// Start workers
 	numWorkers := 12 // Define the number of workers in the pool
 	for w := 0; w < numWorkers; w++ {
-		go a.worker(ctx, graph, a.subscriptions, jobs, ch, &wg)
+		go a.worker(ctx, graph, a.subscriptions, jobs, batchResults, checkpoint, &wg)
 	}
-	wg.Add(batches)
 
 	batchSize := 12
 	for i := 0; i < len(rules); i += batchSize {
+		index := i / batchSize
+		if res, ok := previous.AprlBatches[index]; ok {
+			log.Debug().Msgf("Skipping APRL batch %d. Already completed", index)
+			batchResults[index] = aprlBatchResult{results: res}
+			continue
+		}
+
 		j := i + batchSize
 		if j > len(rules) {
 			j = len(rules)
 		}
 
-		jobs <- rules[i:j]
-
-		// Staggering queries to avoid throttling. Max 15 queries each 5 seconds.
-		// https://learn.microsoft.com/en-us/azure/governance/resource-graph/concepts/guidance-for-throttled-requests#staggering-queries
-		time.Sleep(5 * time.Second)
+		wg.Add(1)
+		jobs <- aprlBatch{index: index, rules: rules[i:j]}
 	}
 
 	// Wait for all workers to finish
 	close(jobs)
 	wg.Wait()
 
-	for i := 0; i < batches; i++ {
-		res := <-ch
-		for _, r := range res {
-			if a.filters.Azqr.IsServiceExcluded(r.ResourceID) {
+	// merge the batches in order, so the results are deterministic
+	for _, res := range batchResults {
+		scanErrors = append(scanErrors, res.errors...)
+		results = append(results, a.filterResults(res.results, packTypes)...)
+	}
+
+	return recommendations, results, scanErrors
+}
+
+// filterResults removes the results of the excluded resources. The resource types of the rule packs
+// without a service scanner are not selected by the filters, so only their scope is checked
+func (a AprlScanner) filterResults(results []scanners.AprlResult, packTypes map[string]bool) []scanners.AprlResult {
+	filtered := []scanners.AprlResult{}
+	for _, r := range results {
+		if packTypes[strings.ToLower(r.ResourceType)] {
+			if a.filters.Azqr.IsResourceExcluded(r.ResourceID) {
 				continue
 			}
-			results = append(results, r)
+		} else if a.filters.Azqr.IsServiceExcluded(r.ResourceID) {
+			continue
 		}
+		filtered = append(filtered, r)
 	}
+	return filtered
+}
 
-	return recommendations, results
+// aprlBatch - batch of APRL recommendations queried by a worker
+type aprlBatch struct {
+	index int
+	rules []scanners.AprlRecommendation
 }
 
-func (a *AprlScanner) worker(ctx context.Context, graph *graph.GraphQuery, subscriptions map[string]string, jobs <-chan []scanners.AprlRecommendation, results chan<- []scanners.AprlResult, wg *sync.WaitGroup) {
-	for r := range jobs {
-		res, err := a.graphScan(ctx, graph, r, subscriptions)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to scan")
+// aprlBatchResult - result of a single batch of APRL graph queries
+type aprlBatchResult struct {
+	results []scanners.AprlResult
+	errors  []scanners.ScanError
+}
+
+func (a *AprlScanner) worker(ctx context.Context, graph *graph.GraphQuery, subscriptions map[string]string, jobs <-chan aprlBatch, results []aprlBatchResult, checkpoint *state.Checkpoint, wg *sync.WaitGroup) {
+	for b := range jobs {
+		res, errs := a.graphScan(ctx, graph, b.rules, subscriptions)
+		results[b.index] = aprlBatchResult{results: res, errors: errs}
+
+		// batches with errors are not saved, so they are queried again when the scan is resumed
+		if len(errs) == 0 {
+			saveCheckpoint(checkpoint, func(s *state.State) { s.AprlBatches[b.index] = res })
 		}
-		results <- res
 		wg.Done()
 	}
 }
 
-func (a AprlScanner) graphScan(ctx context.Context, graphClient *graph.GraphQuery, rules []scanners.AprlRecommendation, subscriptions map[string]string) ([]scanners.AprlResult, error) {
+func (a AprlScanner) graphScan(ctx context.Context, graphClient *graph.GraphQuery, rules []scanners.AprlRecommendation, subscriptions map[string]string) ([]scanners.AprlResult, []scanners.ScanError) {
 	results := []scanners.AprlResult{}
+	scanErrors := []scanners.ScanError{}
 	subs := make([]*string, 0, len(subscriptions))
 	for s := range subscriptions {
 		subs = append(subs, &s)
 	}
 
-	sentQueries := 0
 	for _, rule := range rules {
 		if rule.GraphQuery != "" {
-			result := graphClient.Query(ctx, rule.GraphQuery, subs)
+			result, err := graphClient.Query(ctx, rule.GraphQuery, subs)
+			if err != nil {
+				// report the failed recommendation and continue with the rest of the batch
+				scanErrors = append(scanErrors, scanners.NewScanError(scanners.PhaseAprl, "", "", rule.RecommendationID, err))
+				continue
+			}
 			if result.Data != nil {
 				for _, row := range result.Data {
 					m := row.(map[string]interface{})
@@ -257,6 +352,11 @@ func (a AprlScanner) graphScan(ctx context.Context, graphClient *graph.GraphQuer
 						subscriptionName = ""
 					}
 
+					var remediation *scanners.Remediation
+					if rule.HasAutomation() {
+						remediation = rule.Remediation
+					}
+
 					results = append(results, scanners.AprlResult{
 						RecommendationID:    rule.RecommendationID,
 						Category:            scanners.RecommendationCategory(rule.Category),
@@ -279,19 +379,39 @@ func (a AprlScanner) graphScan(ctx context.Context, graphClient *graph.GraphQuer
 						Learn:               rule.LearnMoreLink[0].Url,
 						AutomationAvailable: rule.AutomationAvailable,
 						Source:              rule.Source,
+						Remediation:         remediation,
 					})
 				}
 			}
-			sentQueries++
-			if sentQueries == 2 {
-				// Staggering queries to avoid throttling. Max 10 queries each 5 seconds.
-				// https://learn.microsoft.com/en-us/azure/governance/resource-graph/concepts/guidance-for-throttled-requests#staggering-queries
-				time.Sleep(1 * time.Second)
-			}
 		}
 	}
 
-	return results, nil
+	return results, scanErrors
+}
+
+// rulePackTypes returns the resource types of the rule packs that no service scanner scans, sorted.
+// The recommendations of the resource types of a service scanner are only queried when the scanner is selected
+func (a AprlScanner) rulePackTypes() []string {
+	_, all := scanners.GetScanners()
+	owned := map[string]bool{}
+	for _, s := range all {
+		for _, t := range s.ResourceTypes() {
+			owned[strings.ToLower(t)] = true
+		}
+	}
+
+	types := []string{}
+	seen := map[string]bool{}
+	for _, p := range a.rulePacks {
+		for t := range p.recommendations {
+			if !owned[t] && !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
 }
 
 func (a AprlScanner) getGraphRules(service string, aprl map[string]map[string]scanners.AprlRecommendation) map[string]scanners.AprlRecommendation {
@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type fakeTransport struct {
+	calls int
+}
+
+func (f *fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	f.calls++
+	body := "response"
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		body = "response to " + string(b)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	transport := &fakeTransport{}
+
+	recorder, err := NewRecorder(dir, transport)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	get, _ := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions?api-version=2022-12-01", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://management.azure.com/providers/Microsoft.ResourceGraph/resources", strings.NewReader("query"))
+	cost, _ := http.NewRequest(http.MethodPost, "https://management.azure.com/subscriptions/0000/providers/Microsoft.CostManagement/query?api-version=2023-03-01", strings.NewReader("2024-05-01"))
+	for _, req := range []*http.Request{get, post, cost} {
+		resp, err := recorder.Do(req)
+		if err != nil {
+			t.Fatalf("Recorder.Do() error = %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	player, err := NewPlayer(dir)
+	if err != nil {
+		t.Fatalf("NewPlayer() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		want   string
+	}{
+		{"GET", http.MethodGet, "https://management.azure.com/subscriptions?api-version=2022-12-01", "", "response"},
+		{"POST same body", http.MethodPost, "https://management.azure.com/providers/Microsoft.ResourceGraph/resources", "query", "response to query"},
+		{"POST dated endpoint with a different body", http.MethodPost, "https://management.azure.com/subscriptions/0000/providers/Microsoft.CostManagement/query?api-version=2023-03-01", "2024-06-01", "response to 2024-05-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, _ := http.NewRequest(tt.method, tt.url, body)
+			resp, err := player.Do(req)
+			if err != nil {
+				t.Fatalf("Player.Do() error = %v", err)
+			}
+			got, _ := io.ReadAll(resp.Body)
+			if string(got) != tt.want {
+				t.Errorf("Player.Do() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if transport.calls != 3 {
+		t.Errorf("transport calls = %d, want 3", transport.calls)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://management.azure.com/unknown", nil)
+	if _, err := player.Do(req); err == nil {
+		t.Error("Player.Do() error = nil for an unrecorded request")
+	}
+
+	// the body of a query selects its response, so a different query must not get the recorded one
+	req, _ = http.NewRequest(http.MethodPost, "https://management.azure.com/providers/Microsoft.ResourceGraph/resources", strings.NewReader("other"))
+	if _, err := player.Do(req); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("Player.Do() error = %v for a request with a different body", err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions?api-version=2022-12-01", nil)
+	if _, err := player.Do(req); err == nil {
+		t.Error("Player.Do() error = nil for a request sent more times than recorded")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/clouds"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azqr/internal/state"
+	"github.com/rs/zerolog/log"
+)
+
+// newCheckpoint loads the state of the scan being resumed and creates the checkpoint that saves the progress of this scan.
+// It returns the previous state, which is empty when the scan is not resumed, and the output file name to use.
+func (sc Scanner) newCheckpoint(params *ScanParams, filters *scanners.Filters, outputFile string) (*state.State, *state.Checkpoint, string, error) {
+	scope, err := sc.scanScope(params, filters)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	previous := state.New(scope, outputFile)
+	path := ""
+	if params.Checkpoint {
+		path = fmt.Sprintf("%s.state.json", outputFile)
+	}
+
+	if params.ResumeFile != "" {
+		previous, err = state.Load(params.ResumeFile)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if previous.Scope != scope {
+			return nil, nil, "", fmt.Errorf("state file %s was saved by a scan with a different scope or options", params.ResumeFile)
+		}
+
+		// keep the output file name of the interrupted scan, unless a new one is given
+		if params.OutputName == "" && previous.OutputFileName != "" {
+			outputFile = previous.OutputFileName
+		}
+		path = params.ResumeFile
+		log.Info().Msgf("Resuming scan from %s", params.ResumeFile)
+	}
+
+	current := previous.Clone()
+	current.OutputFileName = outputFile
+	if path != "" {
+		log.Info().Msgf("Saving scan progress to %s", path)
+	}
+	return previous, state.NewCheckpoint(path, current), outputFile, nil
+}
+
+// scanScope returns a fingerprint of the parameters that change the results of a scan,
+// so a state file is only resumed by an equivalent scan
+func (sc Scanner) scanScope(params *ScanParams, filters *scanners.Filters) (string, error) {
+	keys := append([]string{}, params.ScannerKeys...)
+	sort.Strings(keys)
+
+	azureCloud, err := clouds.Get(params.Cloud)
+	if err != nil {
+		return "", err
+	}
+
+	scope := struct {
+		Cloud                  string
+		TenantID               string
+		ManagementGroupID      string
+		SubscriptionID         string
+		ResourceGroup          string
+		ScannerKeys            []string
+		Defender               bool
+		Advisor                bool
+		Cost                   bool
+		UseAzqrRecommendations bool
+		Include                *scanners.IncludeFilter
+		Exclude                *scanners.ExcludeFilter
+		Suppressions           []scanners.Suppression
+		AzqrRulesDirs          []string
+		RulesDirs              []string
+		PolicyDir              string
+		NamingPolicy           string
+		TagPolicy              string
+		WorkloadsFile          string
+		Remediation            bool
+		ReplayDir              string
+	}{
+		Cloud:                  azureCloud.Name,
+		TenantID:               strings.ToLower(params.TenantID),
+		ManagementGroupID:      params.ManagementGroupID,
+		SubscriptionID:         params.SubscriptionID,
+		ResourceGroup:          params.ResourceGroup,
+		ScannerKeys:            keys,
+		Defender:               params.Defender,
+		Advisor:                params.Advisor,
+		Cost:                   params.Cost,
+		UseAzqrRecommendations: params.UseAzqrRecommendations,
+		Include:                filters.Azqr.Include,
+		Exclude:                filters.Azqr.Exclude,
+		Suppressions:           filters.Azqr.Suppressions,
+		AzqrRulesDirs:          params.AzqrRulesDirs,
+		RulesDirs:              params.RulesDirs,
+		PolicyDir:              params.PolicyDir,
+		NamingPolicy:           params.NamingPolicy,
+		TagPolicy:              params.TagPolicy,
+		WorkloadsFile:          params.WorkloadsFile,
+		Remediation:            params.Remediation,
+		ReplayDir:              params.ReplayDir,
+	}
+
+	content, err := json.Marshal(scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute scan scope: %w", err)
+	}
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// saveCheckpoint updates the scan state. Failing to save the state does not stop the scan
+func saveCheckpoint(checkpoint *state.Checkpoint, update func(s *state.State)) {
+	if err := checkpoint.Update(update); err != nil {
+		log.Warn().Err(err).Msg("Failed to save the scan state")
+	}
+}
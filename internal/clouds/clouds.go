@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package clouds maps the names of the Azure clouds to their configuration and portal.
+package clouds
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// Cloud - Azure cloud
+type Cloud struct {
+	// Name is the name used by the Azure CLI (e.g. AzureUSGovernment)
+	Name string
+	// Configuration is used by the credentials and the ARM clients
+	Configuration cloud.Configuration
+	// Portal is the URL of the Azure portal
+	Portal string
+}
+
+var (
+	AzurePublic = Cloud{
+		Name:          "AzureCloud",
+		Configuration: cloud.AzurePublic,
+		Portal:        "https://portal.azure.com",
+	}
+	AzureGovernment = Cloud{
+		Name:          "AzureUSGovernment",
+		Configuration: cloud.AzureGovernment,
+		Portal:        "https://portal.azure.us",
+	}
+	AzureChina = Cloud{
+		Name:          "AzureChinaCloud",
+		Configuration: cloud.AzureChina,
+		Portal:        "https://portal.azure.cn",
+	}
+
+	all = []Cloud{AzurePublic, AzureGovernment, AzureChina}
+
+	// aliases of the cloud names, lower case
+	aliases = map[string]Cloud{
+		"":                  AzurePublic,
+		"azurecloud":        AzurePublic,
+		"public":            AzurePublic,
+		"azureusgovernment": AzureGovernment,
+		"usgovernment":      AzureGovernment,
+		"usgov":             AzureGovernment,
+		"azurechinacloud":   AzureChina,
+		"china":             AzureChina,
+	}
+)
+
+// Get returns the cloud with the given name. An empty name returns the public cloud
+func Get(name string) (Cloud, error) {
+	c, ok := aliases[strings.ToLower(name)]
+	if !ok {
+		return Cloud{}, fmt.Errorf("unknown cloud %s. Supported clouds: %s", name, strings.Join(Names(), ", "))
+	}
+	return c, nil
+}
+
+// Names returns the names of the supported clouds
+func Names() []string {
+	names := make([]string, 0, len(all))
+	for _, c := range all {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// PortalLink returns the portal link with the host of the cloud's portal
+func (c Cloud) PortalLink(link string) string {
+	if link == "" {
+		return ""
+	}
+
+	portal, err := url.Parse(c.Portal)
+	if err != nil {
+		return link
+	}
+
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	u.Scheme = portal.Scheme
+	u.Host = portal.Host
+	return u.String()
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package clouds
+
+import (
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	tests := map[string]string{
+		"":                  "AzureCloud",
+		"AzureCloud":        "AzureCloud",
+		"azureusgovernment": "AzureUSGovernment",
+		"usgov":             "AzureUSGovernment",
+		"AzureChinaCloud":   "AzureChinaCloud",
+	}
+	for name, want := range tests {
+		c, err := Get(name)
+		if err != nil {
+			t.Fatalf("Get(%q) failed: %v", name, err)
+		}
+		if c.Name != want {
+			t.Errorf("Get(%q) = %s, want %s", name, c.Name, want)
+		}
+	}
+
+	if _, err := Get("AzureGermanCloud"); err == nil {
+		t.Error("expected an error for an unknown cloud")
+	}
+}
+
+func TestPortalLink(t *testing.T) {
+	link := "https://portal.azure.com/#blade/Microsoft_Azure_Security/RecommendationsBlade/assessmentKey/1"
+	want := "https://portal.azure.us/#blade/Microsoft_Azure_Security/RecommendationsBlade/assessmentKey/1"
+	if got := AzureGovernment.PortalLink(link); got != want {
+		t.Errorf("PortalLink() = %s, want %s", got, want)
+	}
+	if got := AzureGovernment.PortalLink(""); got != "" {
+		t.Errorf("PortalLink() = %s, want an empty link", got)
+	}
+}
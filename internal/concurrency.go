@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// concurrencyPolicy - pipeline policy that limits the number of in-flight ARM requests.
+// The same policy instance is shared by all the clients of a scan, so the limit is global.
+type concurrencyPolicy struct {
+	sem chan struct{}
+}
+
+// newConcurrencyPolicy creates a policy that allows up to limit in-flight requests
+func newConcurrencyPolicy(limit int) *concurrencyPolicy {
+	if limit < 1 {
+		limit = 1
+	}
+	return &concurrencyPolicy{
+		sem: make(chan struct{}, limit),
+	}
+}
+
+// Do waits for a free slot before sending the request
+func (p *concurrencyPolicy) Do(req *policy.Request) (*http.Response, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-req.Raw().Context().Done():
+		return nil, req.Raw().Context().Err()
+	}
+	defer func() { <-p.sem }()
+
+	return req.Next()
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+type countingTransport struct {
+	inFlight atomic.Int32
+	max      atomic.Int32
+}
+
+func (c *countingTransport) Do(req *http.Request) (*http.Response, error) {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		m := c.max.Load()
+		if n <= m || c.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestConcurrencyPolicy_LimitsInFlightRequests(t *testing.T) {
+	transport := &countingTransport{}
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{newConcurrencyPolicy(3)},
+	}, &policy.ClientOptions{Transport: transport})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://management.azure.com/")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := pl.Do(req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := transport.max.Load(); got > 3 {
+		t.Errorf("max in-flight requests = %d, want <= 3", got)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/clouds"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/rs/zerolog/log"
+)
+
+// Authentication modes
+const (
+	AuthDefault           = "default"
+	AuthCli               = "cli"
+	AuthManagedIdentity   = "managed-identity"
+	AuthWorkloadIdentity  = "workload-identity"
+	AuthClientCertificate = "client-certificate"
+	AuthDeviceCode        = "device-code"
+)
+
+// AuthModes returns the supported authentication modes
+func AuthModes() []string {
+	return []string{AuthDefault, AuthCli, AuthManagedIdentity, AuthWorkloadIdentity, AuthClientCertificate, AuthDeviceCode}
+}
+
+// authMode returns the authentication mode of the scan parameters
+func (sc Scanner) authMode(params *ScanParams) (string, error) {
+	mode := strings.ToLower(params.Auth)
+	if mode == "" {
+		mode = AuthDefault
+		if params.ForceAzureCliCredential {
+			mode = AuthCli
+		}
+	}
+
+	for _, m := range AuthModes() {
+		if m == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown authentication mode %s. Supported modes: %s", params.Auth, strings.Join(AuthModes(), ", "))
+}
+
+// newAzureCredential creates the credential of the authentication mode
+func (sc Scanner) newAzureCredential(ctx context.Context, params *ScanParams, azureCloud clouds.Cloud) (azcore.TokenCredential, error) {
+	mode, err := sc.authMode(params)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := policy.ClientOptions{Cloud: azureCloud.Configuration}
+
+	var cred azcore.TokenCredential
+	switch mode {
+	case AuthCli:
+		// the Azure CLI credential uses the cloud selected with az cloud set, which must be the scanned cloud
+		if err := sc.checkAzureCliCloud(ctx, azureCloud); err != nil {
+			return nil, err
+		}
+		cred, err = azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{
+			TenantID: params.TenantID,
+		})
+	case AuthManagedIdentity:
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if params.ClientID != "" {
+			// user-assigned managed identity
+			options.ID = azidentity.ClientID(params.ClientID)
+		}
+		cred, err = azidentity.NewManagedIdentityCredential(options)
+	case AuthWorkloadIdentity:
+		cred, err = azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			ClientID:      params.ClientID,
+			TenantID:      params.TenantID,
+		})
+	case AuthClientCertificate:
+		cred, err = sc.newClientCertificateCredential(params, clientOptions)
+	case AuthDeviceCode:
+		cred, err = azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
+			ClientOptions: clientOptions,
+			ClientID:      params.ClientID,
+			TenantID:      params.TenantID,
+		})
+	default:
+		cred, err = azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: clientOptions,
+			TenantID:      params.TenantID,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s credential: %w", mode, err)
+	}
+	return cred, nil
+}
+
+// checkAzureCliCloud fails when the cloud selected in the Azure CLI is not the scanned cloud.
+// The check is skipped with a warning when the Azure CLI cannot be run
+func (sc Scanner) checkAzureCliCloud(ctx context.Context, azureCloud clouds.Cloud) error {
+	name, err := azureCliCloud(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get the cloud selected in the Azure CLI")
+		return nil
+	}
+
+	cliCloud, err := clouds.Get(name)
+	if err != nil || cliCloud.Name != azureCloud.Name {
+		return fmt.Errorf("the Azure CLI uses the %s cloud, but %s is scanned. Run az cloud set --name %s or use --cloud %s", name, azureCloud.Name, azureCloud.Name, name)
+	}
+	return nil
+}
+
+// azureCliCloud returns the name of the cloud selected in the Azure CLI with az cloud set
+var azureCliCloud = func(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "az", "cloud", "show", "--query", "name", "--output", "tsv").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// newClientCertificateCredential creates a service principal credential with a certificate.
+// Missing options are read from the AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_CLIENT_CERTIFICATE_PATH environment variables
+func (sc Scanner) newClientCertificateCredential(params *ScanParams, clientOptions policy.ClientOptions) (azcore.TokenCredential, error) {
+	clientID := params.ClientID
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	tenantID := params.TenantID
+	if tenantID == "" {
+		tenantID = os.Getenv("AZURE_TENANT_ID")
+	}
+	path := params.ClientCertificate
+	if path == "" {
+		path = os.Getenv("AZURE_CLIENT_CERTIFICATE_PATH")
+	}
+	if path == "" || clientID == "" || tenantID == "" {
+		return nil, errors.New("client certificate authentication requires a certificate, a client id and a tenant id")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client certificate %s: %w", path, err)
+	}
+
+	var password []byte
+	if p := os.Getenv("AZURE_CLIENT_CERTIFICATE_PASSWORD"); p != "" {
+		password = []byte(p)
+	}
+	certs, key, err := azidentity.ParseCertificates(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate %s: %w", path, err)
+	}
+
+	return azidentity.NewClientCertificateCredential(tenantID, clientID, certs, key, &azidentity.ClientCertificateCredentialOptions{
+		ClientOptions: clientOptions,
+	})
+}
+
+// validateCredential requests an ARM token, so authentication errors are reported before scanning
+func (sc Scanner) validateCredential(ctx context.Context, cred azcore.TokenCredential, azureCloud clouds.Cloud) error {
+	audience := azureCloud.Configuration.Services[cloud.ResourceManager].Audience
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	_, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{strings.TrimSuffix(audience, "/") + "/.default"},
+	})
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	return nil
+}
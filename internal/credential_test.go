@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/cassette"
+	"github.com/Azure/azqr/internal/clouds"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+type failingCredential struct {
+	scopes []string
+}
+
+func (c *failingCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.scopes = options.Scopes
+	return azcore.AccessToken{}, errors.New("invalid client secret")
+}
+
+func TestAuthMode(t *testing.T) {
+	tests := []struct {
+		params ScanParams
+		want   string
+	}{
+		{ScanParams{}, AuthDefault},
+		{ScanParams{ForceAzureCliCredential: true}, AuthCli},
+		{ScanParams{Auth: "Managed-Identity"}, AuthManagedIdentity},
+		{ScanParams{Auth: AuthDeviceCode, ForceAzureCliCredential: true}, AuthDeviceCode},
+	}
+	for _, tt := range tests {
+		got, err := Scanner{}.authMode(&tt.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("authMode(%+v) = %s, want %s", tt.params, got, tt.want)
+		}
+	}
+
+	if _, err := (Scanner{}).authMode(&ScanParams{Auth: "password"}); err == nil {
+		t.Error("expected an error for an unknown authentication mode")
+	}
+}
+
+func TestNewAzureCredential_ClientCertificateRequiresOptions(t *testing.T) {
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_TENANT_ID", "")
+	t.Setenv("AZURE_CLIENT_CERTIFICATE_PATH", "")
+
+	_, err := Scanner{}.newAzureCredential(context.Background(), &ScanParams{Auth: AuthClientCertificate}, clouds.AzurePublic)
+	if err == nil {
+		t.Error("expected an error without certificate, client id and tenant id")
+	}
+}
+
+func TestValidateCredential(t *testing.T) {
+	cred := &failingCredential{}
+	err := Scanner{}.validateCredential(context.Background(), cred, clouds.AzureGovernment)
+	if err == nil || !strings.HasPrefix(err.Error(), "authentication failed") {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+	if len(cred.scopes) != 1 || cred.scopes[0] != "https://management.core.usgovcloudapi.net/.default" {
+		t.Errorf("unexpected token scopes: %v", cred.scopes)
+	}
+
+	if err := (Scanner{}).validateCredential(context.Background(), cassette.Credential{}, clouds.AzurePublic); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewAzureCredential_CliCloud(t *testing.T) {
+	previous := azureCliCloud
+	defer func() { azureCliCloud = previous }()
+	azureCliCloud = func(ctx context.Context) (string, error) { return "AzureCloud", nil }
+
+	params := &ScanParams{Auth: AuthCli}
+	if _, err := (Scanner{}).newAzureCredential(context.Background(), params, clouds.AzureGovernment); err == nil {
+		t.Error("expected an error when the Azure CLI uses another cloud")
+	}
+	if _, err := (Scanner{}).newAzureCredential(context.Background(), params, clouds.AzurePublic); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -5,10 +5,15 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	arg "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
 	"github.com/rs/zerolog/log"
 )
@@ -19,26 +24,33 @@ type (
 	}
 
 	GraphResult struct {
-		Data  []interface{}
+		Data []interface{}
 	}
 )
 
-func NewGraphQuery(cred azcore.TokenCredential) *GraphQuery {
-	client, err := arg.NewClient(cred, nil)
+// NewGraphQuery creates a new Resource Graph query client
+func NewGraphQuery(cred azcore.TokenCredential, options *arm.ClientOptions) (*GraphQuery, error) {
+	client, err := arg.NewClient(cred, options)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create Resource Graph client")
-		return nil
+		return nil, fmt.Errorf("failed to create Resource Graph client: %w", err)
 	}
 	return &GraphQuery{
 		client: client,
-	}
+	}, nil
 }
 
-func (q *GraphQuery) Query(ctx context.Context, query string, subscriptions []*string) *GraphResult {
+// Query runs the Resource Graph query against the given subscriptions, following skip tokens
+func (q *GraphQuery) Query(ctx context.Context, query string, subscriptions []*string) (*GraphResult, error) {
 	result := GraphResult{
 		Data: make([]interface{}, 0),
 	}
 
+	// Sort the subscriptions so the batches (and requests) are the same on every run
+	subscriptions = slices.Clone(subscriptions)
+	slices.SortFunc(subscriptions, func(a, b *string) int {
+		return strings.Compare(*a, *b)
+	})
+
 	// Run the query in batches of 300 subscriptions
 	batchSize := 300
 	for i := 0; i < len(subscriptions); i += batchSize {
@@ -58,7 +70,7 @@ func (q *GraphQuery) Query(ctx context.Context, query string, subscriptions []*s
 		}
 
 		if q.client == nil {
-			log.Fatal().Msg("Resource Graph client not initialized")
+			return nil, errors.New("resource Graph client not initialized")
 		}
 
 		var skipToken *string = nil
@@ -70,18 +82,18 @@ func (q *GraphQuery) Query(ctx context.Context, query string, subscriptions []*s
 				result.Data = append(result.Data, results.Data.([]interface{})...)
 				skipToken = results.SkipToken
 			} else {
-				log.Fatal().Err(err).Msgf("Failed to run Resource Graph query: %s", query)
-				return nil
+				return nil, fmt.Errorf("failed to run Resource Graph query: %s: %w", query, err)
 			}
 		}
 	}
-	return &result
+	return &result, nil
 }
 
 func (q *GraphQuery) retry(ctx context.Context, attempts int, sleep time.Duration, request arg.QueryRequest) (arg.ClientResourcesResponse, error) {
 	var err error
 	for i := 0; ; i++ {
-		res, err := q.client.Resources(ctx, request, nil)
+		var res arg.ClientResourcesResponse
+		res, err = q.client.Resources(ctx, request, nil)
 		if err == nil {
 			return res, nil
 		}
@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// Resource Graph throttling headers
+// https://learn.microsoft.com/en-us/azure/governance/resource-graph/concepts/guidance-for-throttled-requests#understand-throttling-headers
+const (
+	headerQuotaRemaining   = "x-ms-user-quota-remaining"
+	headerQuotaResetsAfter = "x-ms-user-quota-resets-after"
+	headerRetryAfter       = "Retry-After"
+	headerRetryAfterMs     = "retry-after-ms"
+	headerXMsRetryAfterMs  = "x-ms-retry-after-ms"
+)
+
+// RateLimiter - pipeline policy that throttles the Resource Graph and ARM batch requests.
+// It tracks the quota reported by the x-ms-user-quota-remaining and x-ms-user-quota-resets-after headers,
+// and holds every request when the quota is exhausted or a Retry-After is received.
+// The same instance is shared by all the clients of a scan, so the quota is shared by all the callers.
+type RateLimiter struct {
+	mu           sync.Mutex
+	remaining    int
+	resetAt      time.Time
+	blockedUntil time.Time
+	now          func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter with an unknown quota
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		remaining: -1,
+		now:       time.Now,
+	}
+}
+
+// Do waits until the quota allows the request, sends it and updates the quota from the response headers.
+// Requests to other endpoints are sent without throttling.
+func (l *RateLimiter) Do(req *policy.Request) (*http.Response, error) {
+	if !isThrottled(req.Raw()) {
+		return req.Next()
+	}
+
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			break
+		}
+
+		log.Debug().Msgf("Resource Graph quota exhausted. Waiting %s", wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Raw().Context().Done():
+			timer.Stop()
+			return nil, req.Raw().Context().Err()
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+
+	l.update(resp)
+	return resp, nil
+}
+
+// reserve takes one request from the quota, or returns how long to wait for the quota to reset
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	if l.remaining >= 0 && !now.Before(l.resetAt) {
+		// the quota window is over. The next response reports the new quota
+		l.remaining = -1
+	}
+
+	if l.remaining == 0 {
+		return l.resetAt.Sub(now)
+	}
+
+	if l.remaining > 0 {
+		l.remaining--
+	}
+	return 0
+}
+
+// update sets the quota from the response headers
+func (l *RateLimiter) update(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	if v := resp.Header.Get(headerQuotaRemaining); v != "" {
+		if remaining, err := strconv.Atoi(v); err == nil {
+			resetsAfter := parseResetsAfter(resp.Header.Get(headerQuotaResetsAfter))
+			l.remaining = remaining
+			l.resetAt = now.Add(resetsAfter)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header, now)
+		if retryAfter <= 0 && l.remaining == 0 {
+			retryAfter = l.resetAt.Sub(now)
+		}
+		if until := now.Add(retryAfter); until.After(l.blockedUntil) {
+			l.blockedUntil = until
+		}
+		log.Debug().Msgf("Resource Graph request throttled. Retrying after %s", retryAfter)
+	}
+}
+
+// isThrottled returns true for the Resource Graph queries and the ARM batch requests
+func isThrottled(req *http.Request) bool {
+	path := strings.ToLower(strings.TrimSuffix(req.URL.Path, "/"))
+	return strings.HasSuffix(path, "/providers/microsoft.resourcegraph/resources") || path == "/batch"
+}
+
+// parseResetsAfter parses the hh:mm:ss format of the x-ms-user-quota-resets-after header
+func parseResetsAfter(v string) time.Duration {
+	parts := strings.Split(v, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second))
+}
+
+// parseRetryAfter parses the retry-after-ms, x-ms-retry-after-ms and Retry-After (seconds or HTTP date) headers
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	for _, name := range []string{headerRetryAfterMs, headerXMsRetryAfterMs} {
+		if v := h.Get(name); v != "" {
+			if ms, err := strconv.Atoi(v); err == nil {
+				return time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	v := h.Get(headerRetryAfter)
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
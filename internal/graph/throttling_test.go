@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+type quotaTransport struct {
+	header http.Header
+	status int
+	sent   []time.Time
+}
+
+func (q *quotaTransport) Do(req *http.Request) (*http.Response, error) {
+	q.sent = append(q.sent, time.Now())
+	return &http.Response{StatusCode: q.status, Header: q.header.Clone(), Body: http.NoBody, Request: req}, nil
+}
+
+func send(t *testing.T, pl runtime.Pipeline, url string) {
+	t.Helper()
+	req, err := runtime.NewRequest(context.Background(), http.MethodPost, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := pl.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+}
+
+func newPipeline(transport policy.Transporter, limiter *RateLimiter) runtime.Pipeline {
+	return runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{limiter},
+	}, &policy.ClientOptions{Transport: transport, Retry: policy.RetryOptions{MaxRetries: -1}})
+}
+
+func TestRateLimiter_WaitsForQuotaReset(t *testing.T) {
+	transport := &quotaTransport{
+		status: http.StatusOK,
+		header: http.Header{
+			"X-Ms-User-Quota-Remaining":    []string{"0"},
+			"X-Ms-User-Quota-Resets-After": []string{"00:00:00.3"},
+		},
+	}
+	pl := newPipeline(transport, NewRateLimiter())
+
+	url := "https://management.azure.com/providers/Microsoft.ResourceGraph/resources?api-version=2021-03-01"
+	send(t, pl, url)
+	send(t, pl, url)
+
+	if d := transport.sent[1].Sub(transport.sent[0]); d < 250*time.Millisecond {
+		t.Errorf("expected the second request to wait for the quota reset, waited %s", d)
+	}
+}
+
+func TestRateLimiter_HonoursRetryAfter(t *testing.T) {
+	transport := &quotaTransport{
+		status: http.StatusTooManyRequests,
+		header: http.Header{"Retry-After-Ms": []string{"300"}},
+	}
+	pl := newPipeline(transport, NewRateLimiter())
+
+	url := "https://management.azure.com/batch?api-version=2020-06-01"
+	send(t, pl, url)
+	send(t, pl, url)
+
+	if d := transport.sent[1].Sub(transport.sent[0]); d < 250*time.Millisecond {
+		t.Errorf("expected the second request to honour Retry-After, waited %s", d)
+	}
+}
+
+func TestRateLimiter_IgnoresOtherRequests(t *testing.T) {
+	transport := &quotaTransport{
+		status: http.StatusTooManyRequests,
+		header: http.Header{"Retry-After": []string{"60"}},
+	}
+	pl := newPipeline(transport, NewRateLimiter())
+
+	url := "https://management.azure.com/subscriptions?api-version=2022-12-01"
+	send(t, pl, url)
+	send(t, pl, url)
+
+	if d := transport.sent[1].Sub(transport.sent[0]); d > time.Second {
+		t.Errorf("expected requests to other endpoints not to be throttled, waited %s", d)
+	}
+}
+
+func TestParseResetsAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:05":   5 * time.Second,
+		"00:01:02.5": time.Minute + 2500*time.Millisecond,
+		"invalid":    0,
+		"":           0,
+	}
+	for v, want := range tests {
+		if got := parseResetsAfter(v); got != want {
+			t.Errorf("parseResetsAfter(%q) = %s, want %s", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/graph"
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azqr/internal/state"
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/rs/zerolog/log"
+)
+
+// resourceChangesRetention is how long Resource Graph keeps the resource changes
+const resourceChangesRetention = 14 * 24 * time.Hour
+
+// incrementalScan - Resources changed since a previous scan.
+// Only the changed resource types of the changed subscriptions are scanned again,
+// and the results of the previous scan are carried over for the other resources.
+type incrementalScan struct {
+	// baseline is the state of the previous scan. Nil when the scan is based on a timestamp
+	baseline *state.State
+	// subscriptions with changes
+	subscriptions map[string]bool
+	// resourceTypes with changes, queried by the APRL scanner
+	resourceTypes map[string]bool
+	// scannerTypes are the resource types of the AZQR scanners with changes
+	scannerTypes map[string]bool
+	// deleted resources, never carried over
+	deleted map[string]bool
+}
+
+// parseSince parses the --since option: a RFC3339 timestamp, the state file of a previous scan
+// or the findings file of a previous report. A timestamp has no previous results to carry over
+func (sc Scanner) parseSince(since, scope string) (time.Time, *state.State, error) {
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil, nil
+	}
+
+	baseline, stateErr := state.Load(since)
+	if stateErr == nil {
+		if baseline.Scope != scope {
+			return time.Time{}, nil, fmt.Errorf("state file %s was saved by a scan with a different scope or options", since)
+		}
+		if baseline.StartedAt.IsZero() {
+			return time.Time{}, nil, fmt.Errorf("state file %s has no scan time", since)
+		}
+		return baseline.StartedAt, baseline, nil
+	}
+
+	report, err := renderers.LoadFindingsFile(since)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("since must be a RFC3339 timestamp, a state file or a findings file: %w", stateErr)
+	}
+	if report.ScannedAt.IsZero() {
+		return time.Time{}, nil, fmt.Errorf("findings file %s has no scan time, it was written by an older version", since)
+	}
+	return report.ScannedAt, findingsState(report), nil
+}
+
+// findingsState returns a state with the findings of a previous report as results, to carry them over.
+// The report only has the non compliant results
+func findingsState(report *renderers.FindingsFile) *state.State {
+	s := state.New("", "")
+	s.StartedAt = report.ScannedAt
+
+	services := map[string]*scanners.AzqrServiceResult{}
+	ids := []string{}
+	for _, f := range report.Findings {
+		switch f.Scanner {
+		case renderers.FindingScannerAprl:
+			s.AprlBatches[0] = append(s.AprlBatches[0], scanners.AprlResult{
+				RecommendationID: f.RecommendationID,
+				ResourceType:     f.ResourceType,
+				Recommendation:   f.Recommendation,
+				ResourceID:       f.ResourceID,
+				SubscriptionID:   f.SubscriptionID,
+				SubscriptionName: f.SubscriptionName,
+				ResourceGroup:    f.ResourceGroup,
+				Name:             f.ResourceName,
+				Category:         scanners.RecommendationCategory(f.Category),
+				Impact:           scanners.RecommendationImpact(f.Impact),
+				Learn:            f.Learn,
+				Param1:           f.Result,
+				Source:           f.Source,
+				TenantID:         f.TenantID,
+			})
+		case renderers.FindingScannerAzqr:
+			id := strings.ToLower(f.ResourceID)
+			service, ok := services[id]
+			if !ok {
+				service = &scanners.AzqrServiceResult{
+					SubscriptionID:   f.SubscriptionID,
+					SubscriptionName: f.SubscriptionName,
+					ResourceGroup:    f.ResourceGroup,
+					Type:             f.ResourceType,
+					ServiceName:      f.ResourceName,
+					Recommendations:  map[string]scanners.AzqrResult{},
+					TenantID:         f.TenantID,
+					ID:               f.ResourceID,
+				}
+				services[id] = service
+				ids = append(ids, id)
+			}
+			source := f.Source
+			if source == "AZQR" {
+				source = ""
+			}
+			service.Recommendations[f.RecommendationID] = scanners.AzqrResult{
+				RecommendationID: f.RecommendationID,
+				ResourceType:     f.ResourceType,
+				Recommendation:   f.Recommendation,
+				Category:         scanners.RecommendationCategory(f.Category),
+				Impact:           scanners.RecommendationImpact(f.Impact),
+				LearnMoreUrl:     f.Learn,
+				NotCompliant:     true,
+				Status:           scanners.StatusNotCompliant,
+				Result:           f.Result,
+				Source:           source,
+			}
+		}
+	}
+
+	for _, id := range ids {
+		service := services[id]
+		subscriptionID := strings.ToLower(service.SubscriptionID)
+		if s.Subscriptions[subscriptionID] == nil {
+			s.Subscriptions[subscriptionID] = &state.Subscription{}
+		}
+		s.Subscriptions[subscriptionID].Azqr = append(s.Subscriptions[subscriptionID].Azqr, *service)
+	}
+	return s
+}
+
+// newIncrementalScan returns the resources changed since the previous scan.
+// It returns nil, meaning a full scan, when the changes are older than the Resource Graph retention or cannot be queried
+func (sc Scanner) newIncrementalScan(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptions map[string]string, serviceScanners []scanners.IAzureScanner, since time.Time, baseline *state.State) *incrementalScan {
+	if time.Since(since) > resourceChangesRetention {
+		log.Warn().Msgf("Resource changes are only kept for %s. Running a full scan", resourceChangesRetention)
+		return nil
+	}
+
+	log.Info().Msgf("Scanning resources changed since %s", since.UTC().Format(time.RFC3339))
+
+	graphClient, err := graph.NewGraphQuery(cred, options)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to query resource changes. Running a full scan")
+		return nil
+	}
+
+	query := fmt.Sprintf(`resourcechanges
+		| extend changeTime = todatetime(properties.changeAttributes.timestamp)
+		| where changeTime > datetime(%s)
+		| project targetResourceId = tostring(properties.targetResourceId), targetResourceType = tostring(properties.targetResourceType), changeType = tostring(properties.changeType)`,
+		since.UTC().Format(time.RFC3339))
+	log.Debug().Msg(query)
+
+	subs := make([]*string, 0, len(subscriptions))
+	for s := range subscriptions {
+		subs = append(subs, to.Ptr(s))
+	}
+	result, err := graphClient.Query(ctx, query, subs)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to query resource changes. Running a full scan")
+		return nil
+	}
+
+	inc := &incrementalScan{
+		baseline:      baseline,
+		subscriptions: map[string]bool{},
+		resourceTypes: map[string]bool{},
+		scannerTypes:  map[string]bool{},
+		deleted:       map[string]bool{},
+	}
+
+	changed := map[string]bool{}
+	for _, row := range result.Data {
+		m := row.(map[string]interface{})
+		id := strings.ToLower(to.String(m["targetResourceId"]))
+		if id == "" {
+			continue
+		}
+
+		resourceType := strings.ToLower(to.String(m["targetResourceType"]))
+		if resourceType == "" {
+			resourceType = strings.ToLower(scanners.GetResourceTypeFromResourceID(id))
+		}
+
+		changed[id] = true
+		inc.subscriptions[strings.ToLower(scanners.GetSubscriptionFromResourceID(id))] = true
+		inc.resourceTypes[resourceType] = true
+		if strings.EqualFold(to.String(m["changeType"]), "Delete") {
+			inc.deleted[id] = true
+		}
+	}
+
+	// scanners scan all their resource types, so all of them are scanned again
+	for _, s := range serviceScanners {
+		if inc.scanned(s) {
+			for _, t := range s.ResourceTypes() {
+				inc.scannerTypes[strings.ToLower(t)] = true
+			}
+		}
+	}
+
+	log.Info().Msgf("%d resources changed in %d subscriptions", len(changed), len(inc.subscriptions))
+	return inc
+}
+
+// scanned returns true if the scanner has changed resources
+func (i *incrementalScan) scanned(s scanners.IAzureScanner) bool {
+	for _, t := range s.ResourceTypes() {
+		if i.resourceTypes[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceScanners returns the scanners to run for the subscription
+func (i *incrementalScan) serviceScanners(subscriptionID string, serviceScanners []scanners.IAzureScanner) []scanners.IAzureScanner {
+	res := []scanners.IAzureScanner{}
+	if !i.subscriptions[strings.ToLower(subscriptionID)] {
+		return res
+	}
+	for _, s := range serviceScanners {
+		if i.scanned(s) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// changedSubscriptions returns the subscriptions with changes
+func (i *incrementalScan) changedSubscriptions(subscriptions map[string]string) map[string]string {
+	res := map[string]string{}
+	for id, name := range subscriptions {
+		if i.subscriptions[strings.ToLower(id)] {
+			res[id] = name
+		}
+	}
+	return res
+}
+
+// resourceIDs returns the ids of the resources scanned again by the AZQR scanners
+func (i *incrementalScan) resourceIDs(resources []*scanners.Resource) []*string {
+	ids := []*string{}
+	for _, r := range resources {
+		if i.subscriptions[strings.ToLower(r.SubscriptionID)] && i.scannerTypes[strings.ToLower(r.Type)] {
+			ids = append(ids, to.Ptr(r.ID))
+		}
+	}
+	return ids
+}
+
+// carriedAprl returns the APRL results of the previous scan for the resources that were not scanned again
+func (i *incrementalScan) carriedAprl() []scanners.AprlResult {
+	carried := []scanners.AprlResult{}
+	if i.baseline == nil {
+		return carried
+	}
+	for _, r := range i.baseline.AprlResults() {
+		if i.deleted[strings.ToLower(r.ResourceID)] ||
+			(i.subscriptions[strings.ToLower(r.SubscriptionID)] && i.resourceTypes[strings.ToLower(r.ResourceType)]) {
+			continue
+		}
+		carried = append(carried, r)
+	}
+	return carried
+}
+
+// carriedAzqr returns the AZQR results of the previous scan for the resources that were not scanned again
+func (i *incrementalScan) carriedAzqr() []scanners.AzqrServiceResult {
+	carried := []scanners.AzqrServiceResult{}
+	if i.baseline == nil {
+		return carried
+	}
+	for _, r := range i.baseline.AzqrResults() {
+		if i.deleted[r.ResourceID()] ||
+			(i.subscriptions[strings.ToLower(r.SubscriptionID)] && i.scannerTypes[strings.ToLower(r.Type)]) {
+			continue
+		}
+		carried = append(carried, r)
+	}
+	return carried
+}
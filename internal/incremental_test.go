@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azqr/internal/state"
+)
+
+const (
+	changedSubscription   = "11111111-1111-1111-1111-111111111111"
+	unchangedSubscription = "22222222-2222-2222-2222-222222222222"
+)
+
+func newTestIncrementalScan() *incrementalScan {
+	baseline := state.New("scope", "azqr")
+	baseline.AprlBatches[0] = []scanners.AprlResult{
+		{ResourceID: "/subscriptions/" + changedSubscription + "/providers/Microsoft.Fake/working/changed", SubscriptionID: changedSubscription, ResourceType: "Microsoft.Fake/working"},
+		{ResourceID: "/subscriptions/" + changedSubscription + "/providers/Microsoft.Fake/failing/other", SubscriptionID: changedSubscription, ResourceType: "Microsoft.Fake/failing"},
+		{ResourceID: "/subscriptions/" + unchangedSubscription + "/providers/Microsoft.Fake/working/unchanged", SubscriptionID: unchangedSubscription, ResourceType: "Microsoft.Fake/working"},
+		{ResourceID: "/subscriptions/" + unchangedSubscription + "/providers/Microsoft.Fake/failing/deleted", SubscriptionID: unchangedSubscription, ResourceType: "Microsoft.Fake/failing"},
+	}
+	baseline.Subscriptions[changedSubscription] = &state.Subscription{
+		Azqr: []scanners.AzqrServiceResult{
+			{SubscriptionID: changedSubscription, ResourceGroup: "rg", Type: "Microsoft.Fake/working", ServiceName: "changed"},
+			{SubscriptionID: changedSubscription, ResourceGroup: "rg", Type: "Microsoft.Fake/failing", ServiceName: "other"},
+		},
+	}
+	baseline.Subscriptions[unchangedSubscription] = &state.Subscription{
+		Azqr: []scanners.AzqrServiceResult{
+			{SubscriptionID: unchangedSubscription, ResourceGroup: "rg", Type: "Microsoft.Fake/working", ServiceName: "unchanged"},
+			{SubscriptionID: unchangedSubscription, ResourceGroup: "rg", Type: "Microsoft.Fake/failing", ServiceName: "deleted"},
+		},
+	}
+
+	return &incrementalScan{
+		baseline:      baseline,
+		subscriptions: map[string]bool{changedSubscription: true},
+		resourceTypes: map[string]bool{"microsoft.fake/working": true},
+		scannerTypes:  map[string]bool{"microsoft.fake/working": true},
+		deleted: map[string]bool{
+			"/subscriptions/" + unchangedSubscription + "/providers/microsoft.fake/failing/deleted":                   true,
+			"/subscriptions/" + unchangedSubscription + "/resourcegroups/rg/providers/microsoft.fake/failing/deleted": true,
+		},
+	}
+}
+
+func TestIncrementalScan_ServiceScanners(t *testing.T) {
+	inc := newTestIncrementalScan()
+	serviceScanners := []scanners.IAzureScanner{&workingScanner{}, &failingScanner{}}
+
+	ss := inc.serviceScanners(changedSubscription, serviceScanners)
+	if len(ss) != 1 || ss[0].ResourceTypes()[0] != "Microsoft.Fake/working" {
+		t.Errorf("expected only the scanner of the changed type, got %v", ss)
+	}
+
+	if ss := inc.serviceScanners(unchangedSubscription, serviceScanners); len(ss) != 0 {
+		t.Errorf("expected no scanners for an unchanged subscription, got %d", len(ss))
+	}
+}
+
+func TestIncrementalScan_CarriedResults(t *testing.T) {
+	inc := newTestIncrementalScan()
+
+	aprl := inc.carriedAprl()
+	if len(aprl) != 2 {
+		t.Fatalf("expected 2 carried APRL results, got %d", len(aprl))
+	}
+	for _, r := range aprl {
+		if r.SubscriptionID == changedSubscription && r.ResourceType == "Microsoft.Fake/working" {
+			t.Errorf("changed resource type %s carried over", r.ResourceID)
+		}
+		if r.ResourceID == "/subscriptions/"+unchangedSubscription+"/providers/Microsoft.Fake/failing/deleted" {
+			t.Errorf("deleted resource carried over")
+		}
+	}
+
+	azqr := inc.carriedAzqr()
+	if len(azqr) != 2 {
+		t.Fatalf("expected 2 carried AZQR results, got %d", len(azqr))
+	}
+	for _, r := range azqr {
+		if r.ServiceName == "changed" || r.ServiceName == "deleted" {
+			t.Errorf("%s carried over", r.ServiceName)
+		}
+	}
+}
+
+func TestIncrementalScan_NoBaseline(t *testing.T) {
+	inc := newTestIncrementalScan()
+	inc.baseline = nil
+
+	if len(inc.carriedAprl()) != 0 || len(inc.carriedAzqr()) != 0 {
+		t.Error("expected no carried results without a previous scan")
+	}
+}
+
+func TestIncrementalScan_FindingsBaseline(t *testing.T) {
+	rd := renderers.NewReportData("report", false)
+	rd.Aprl = newTestIncrementalScan().baseline.AprlResults()
+	rd.Azqr = []scanners.AzqrServiceResult{
+		{SubscriptionID: changedSubscription, ResourceGroup: "rg", Type: "Microsoft.Fake/working", ServiceName: "changed",
+			Recommendations: map[string]scanners.AzqrResult{"fake-001": {RecommendationID: "fake-001", NotCompliant: true}}},
+		{SubscriptionID: unchangedSubscription, ResourceGroup: "rg", Type: "Microsoft.Fake/working", ServiceName: "unchanged",
+			Recommendations: map[string]scanners.AzqrResult{
+				"fake-001": {RecommendationID: "fake-001", NotCompliant: true},
+				"fake-002": {RecommendationID: "fake-002"},
+			}},
+	}
+
+	inc := newTestIncrementalScan()
+	inc.baseline = findingsState(&renderers.FindingsFile{ScannedAt: time.Now(), Findings: rd.Findings()})
+
+	if aprl := inc.carriedAprl(); len(aprl) != 2 {
+		t.Errorf("expected 2 carried APRL results, got %d", len(aprl))
+	}
+	azqr := inc.carriedAzqr()
+	if len(azqr) != 1 || azqr[0].ServiceName != "unchanged" {
+		t.Fatalf("expected the AZQR results of the unchanged resource, got %v", azqr)
+	}
+	if r, ok := azqr[0].Recommendations["fake-001"]; !ok || !r.NotCompliant || len(azqr[0].Recommendations) != 1 {
+		t.Errorf("expected the not compliant result to be carried over, got %v", azqr[0].Recommendations)
+	}
+	if azqr[0].ResourceID() != rd.Azqr[1].ResourceID() {
+		t.Errorf("unexpected resource id %s", azqr[0].ResourceID())
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	sc := Scanner{}
+
+	since, baseline, err := sc.parseSince("2024-05-01T00:00:00Z", "scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseline != nil || !since.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected since %s", since)
+	}
+
+	report := filepath.Join(t.TempDir(), "report.findings.json")
+	scannedAt := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	findings := []renderers.Finding{{Scanner: renderers.FindingScannerAprl, RecommendationID: "aprl-1", ResourceID: "/subscriptions/0000/resourceGroups/rg/providers/a/b/c"}}
+	if err := renderers.SaveFindings(report, scannedAt, findings); err != nil {
+		t.Fatal(err)
+	}
+	since, baseline, err = sc.parseSince(report, "scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseline == nil || len(baseline.AprlResults()) != 1 || !since.Equal(scannedAt) {
+		t.Errorf("expected the scan time and the findings of the report, got %s", since)
+	}
+
+	if err := renderers.SaveFindings(report, time.Time{}, findings); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sc.parseSince(report, "scope"); err == nil {
+		t.Error("expected an error for a findings file without scan time")
+	}
+
+	path := filepath.Join(t.TempDir(), "azqr.state.json")
+	s := state.New("scope", "azqr")
+	if err := state.NewCheckpoint(path, s).Update(func(*state.State) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	since, baseline, err = sc.parseSince(path, "scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseline == nil || !since.Equal(s.StartedAt) {
+		t.Errorf("expected the scan time of the state file, got %s", since)
+	}
+
+	if _, _, err := sc.parseSince(path, "other"); err == nil {
+		t.Error("expected an error for a state file with a different scope")
+	}
+	if _, _, err := sc.parseSince("yesterday", "scope"); err == nil {
+		t.Error("expected an error for an invalid value")
+	}
+}
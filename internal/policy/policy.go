@@ -0,0 +1,203 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package policy evaluates Rego policies against the ARM JSON of the resources.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+type (
+	// Engine - Rego policies loaded from a directory.
+	// The deny rules of every package are evaluated with the ARM JSON of a resource as input
+	Engine struct {
+		queries []packageQuery
+	}
+
+	packageQuery struct {
+		pkg   string
+		query rego.PreparedEvalQuery
+	}
+
+	// Violation - Result of a deny rule.
+	// A deny rule returns either a message or an object with the id, msg, category, impact, learnMoreUrl and result keys
+	Violation struct {
+		// ID defaults to the package of the policy
+		ID           string
+		Message      string
+		Category     string
+		Impact       string
+		LearnMoreUrl string
+		Result       string
+	}
+
+	// EvalError - Error raised while evaluating the deny rules of a package
+	EvalError struct {
+		Package string
+		Err     error
+	}
+)
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("failed to evaluate policy %s: %v", e.Package, e.Err)
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// Load loads the *.rego files of a directory and its subdirectories. Test files (*_test.rego) are skipped
+func Load(ctx context.Context, dir string) (*Engine, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("policy directory %s is not a directory", dir)
+	}
+
+	modules := []*ast.Module{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".rego") || strings.HasSuffix(path, "_test.rego") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		module, err := ast.ParseModuleWithOpts(path, string(content), ast.ParserOptions{RegoVersion: ast.RegoV1})
+		if err != nil {
+			return fmt.Errorf("failed to parse policy %s: %w", path, err)
+		}
+		modules = append(modules, module)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("policy directory %s has no .rego files", dir)
+	}
+
+	return newEngine(ctx, modules)
+}
+
+func newEngine(ctx context.Context, modules []*ast.Module) (*Engine, error) {
+	packages := map[string]bool{}
+	for _, m := range modules {
+		packages[m.Package.Path.String()] = true
+	}
+
+	names := make([]string, 0, len(packages))
+	for p := range packages {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+
+	engine := &Engine{}
+	for _, p := range names {
+		options := []func(*rego.Rego){rego.Query(p + ".deny")}
+		for _, m := range modules {
+			options = append(options, rego.ParsedModule(m))
+		}
+
+		query, err := rego.New(options...).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile policies: %w", err)
+		}
+		engine.queries = append(engine.queries, packageQuery{pkg: strings.TrimPrefix(p, "data."), query: query})
+	}
+	return engine, nil
+}
+
+// Packages returns the packages of the policies
+func (e *Engine) Packages() []string {
+	packages := make([]string, 0, len(e.queries))
+	for _, q := range e.queries {
+		packages = append(packages, q.pkg)
+	}
+	return packages
+}
+
+// Eval evaluates the deny rules with the given input and returns the violations, sorted by id and message.
+// The evaluation stops at the first package raising an error, returned as an *EvalError
+func (e *Engine) Eval(ctx context.Context, input interface{}) ([]Violation, error) {
+	violations := []Violation{}
+	for _, q := range e.queries {
+		rs, err := q.query.Eval(ctx, rego.EvalInput(input))
+		if err != nil {
+			return nil, &EvalError{Package: q.pkg, Err: err}
+		}
+
+		for _, r := range rs {
+			for _, expression := range r.Expressions {
+				values, ok := expression.Value.([]interface{})
+				if !ok {
+					return nil, &EvalError{Package: q.pkg, Err: fmt.Errorf("deny must be a set, got %T", expression.Value)}
+				}
+				for _, v := range values {
+					violation, err := newViolation(q.pkg, v)
+					if err != nil {
+						return nil, &EvalError{Package: q.pkg, Err: err}
+					}
+					violations = append(violations, violation)
+				}
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].ID != violations[j].ID {
+			return violations[i].ID < violations[j].ID
+		}
+		return violations[i].Message < violations[j].Message
+	})
+	return violations, nil
+}
+
+// newViolation converts a deny value to a Violation
+func newViolation(pkg string, v interface{}) (Violation, error) {
+	switch t := v.(type) {
+	case string:
+		return Violation{ID: pkg, Message: t}, nil
+	case map[string]interface{}:
+		violation := Violation{
+			ID:           stringValue(t, "id"),
+			Message:      stringValue(t, "msg"),
+			Category:     stringValue(t, "category"),
+			Impact:       stringValue(t, "impact"),
+			LearnMoreUrl: stringValue(t, "learnMoreUrl"),
+			Result:       stringValue(t, "result"),
+		}
+		if violation.ID == "" {
+			violation.ID = pkg
+		}
+		return violation, nil
+	}
+	return Violation{}, fmt.Errorf("deny of policy %s must return strings or objects, got %T", pkg, v)
+}
+
+func stringValue(m map[string]interface{}, key string) string {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const storagePolicy = `package azqr.storage
+
+deny contains msg if {
+	input.type == "Microsoft.Storage/storageAccounts"
+	not input.properties.allowSharedKeyAccess == false
+	msg := "Storage Account should disable Shared Key authorization"
+}
+
+deny contains {"id": "contoso-st-002", "msg": "Storage Account should use TLS 1.2", "category": "Security", "impact": "High", "result": input.properties.minimumTlsVersion} if {
+	input.type == "Microsoft.Storage/storageAccounts"
+	input.properties.minimumTlsVersion != "TLS1_2"
+}
+`
+
+func writePolicies(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestEngine_Eval(t *testing.T) {
+	dir := writePolicies(t, map[string]string{
+		"storage.rego":      storagePolicy,
+		"storage_test.rego": "package azqr.storage_test\n\ntest_nothing if { true }\n",
+		"README.md":         "not a policy",
+	})
+
+	engine, err := Load(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := engine.Packages(); len(p) != 1 || p[0] != "azqr.storage" {
+		t.Errorf("unexpected packages %v", p)
+	}
+
+	input := map[string]interface{}{
+		"type": "Microsoft.Storage/storageAccounts",
+		"properties": map[string]interface{}{
+			"minimumTlsVersion": "TLS1_0",
+		},
+	}
+	violations, err := engine.Eval(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", violations)
+	}
+
+	if v := violations[0]; v.ID != "azqr.storage" || v.Message != "Storage Account should disable Shared Key authorization" {
+		t.Errorf("unexpected violation %+v", v)
+	}
+	if v := violations[1]; v.ID != "contoso-st-002" || v.Category != "Security" || v.Impact != "High" || v.Result != "TLS1_0" {
+		t.Errorf("unexpected violation %+v", v)
+	}
+
+	input["properties"] = map[string]interface{}{"minimumTlsVersion": "TLS1_2", "allowSharedKeyAccess": false}
+	violations, err = engine.Eval(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":  writePolicies(t, map[string]string{"README.md": "no policies"}),
+		"syntax": writePolicies(t, map[string]string{"a.rego": "package a\n\ndeny contains msg if {"}),
+	}
+	for name, dir := range tests {
+		if _, err := Load(context.Background(), dir); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := Load(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+// FailOnRule - Rule of the quality gate. The gate fails when more than Threshold findings match the rule
+type FailOnRule struct {
+	// Key is impact, category or count. count matches all findings
+	Key   string
+	Value string
+	// Threshold is the number of matching findings allowed
+	Threshold int
+	text      string
+}
+
+// ParseFailOnRules parses quality gate rules like impact=High, category=Security>5 or count>100
+func ParseFailOnRules(values []string) ([]FailOnRule, error) {
+	rules := []FailOnRule{}
+	for _, v := range values {
+		rule := FailOnRule{text: v}
+		selector := v
+		if i := strings.Index(v, ">"); i >= 0 {
+			threshold, err := strconv.Atoi(strings.TrimSpace(v[i+1:]))
+			if err != nil || threshold < 0 {
+				return nil, fmt.Errorf("invalid fail-on rule %s: the threshold must be a non-negative number", v)
+			}
+			rule.Threshold = threshold
+			selector = v[:i]
+		}
+
+		key, value, _ := strings.Cut(selector, "=")
+		rule.Key = strings.ToLower(strings.TrimSpace(key))
+		rule.Value = strings.TrimSpace(value)
+		switch rule.Key {
+		case "impact", "category":
+			if rule.Value == "" {
+				return nil, fmt.Errorf("invalid fail-on rule %s: expected %s=<value>", v, rule.Key)
+			}
+		case "count":
+			if rule.Value != "" || !strings.Contains(v, ">") {
+				return nil, fmt.Errorf("invalid fail-on rule %s: expected count>N", v)
+			}
+		default:
+			return nil, fmt.Errorf("invalid fail-on rule %s: expected impact=<impact>, category=<category> or count>N", v)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// EvaluateQualityGate returns the rules broken by the findings, with the number of matching findings
+func EvaluateQualityGate(rules []FailOnRule, findings []renderers.Finding) []string {
+	broken := []string{}
+	for _, r := range rules {
+		count := 0
+		for _, f := range findings {
+			if r.matches(f) {
+				count++
+			}
+		}
+		if count > r.Threshold {
+			broken = append(broken, fmt.Sprintf("%s: %d findings", r.text, count))
+		}
+	}
+	return broken
+}
+
+func (r FailOnRule) matches(f renderers.Finding) bool {
+	switch r.Key {
+	case "impact":
+		return strings.EqualFold(f.Impact, r.Value)
+	case "category":
+		return strings.EqualFold(f.Category, r.Value)
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+func TestQualityGate(t *testing.T) {
+	rules, err := ParseFailOnRules([]string{"impact=High", "category=Security>1", "count>3", "impact=low>5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []renderers.Finding{
+		{RecommendationID: "st-007", Impact: "High", Category: "Security"},
+		{RecommendationID: "st-009", Impact: "Low", Category: "Security"},
+		{RecommendationID: "st-010", Impact: "Low", Category: "DisasterRecovery"},
+	}
+	expected := []string{"impact=High: 1 findings", "category=Security>1: 2 findings"}
+	if broken := EvaluateQualityGate(rules, findings); !reflect.DeepEqual(broken, expected) {
+		t.Errorf("expected %v, got %v", expected, broken)
+	}
+
+	if broken := EvaluateQualityGate(rules, findings[2:]); len(broken) != 0 {
+		t.Errorf("expected no broken rules, got %v", broken)
+	}
+}
+
+func TestParseFailOnRules_Invalid(t *testing.T) {
+	for _, v := range []string{"impact", "impact=High>x", "category=Security>-1", "count", "count=3", "severity=High"} {
+		if _, err := ParseFailOnRules([]string{v}); err == nil {
+			t.Errorf("expected an error for %s", v)
+		}
+	}
+}
@@ -13,48 +13,31 @@ import (
 	"github.com/Azure/azqr/internal/renderers"
 )
 
-func CreateCsvReport(data *renderers.ReportData) {
-	records := data.RecommendationsTable()
-	writeData(records, data.OutputFileName, "recommendations")
-
-	records = data.ImpactedTable()
-	writeData(records, data.OutputFileName, "impacted")
-
-	records = data.ResourceTypesTable()
-	writeData(records, data.OutputFileName, "resourceType")
-
-	records = data.ResourcesTable()
-	writeData(records, data.OutputFileName, "inventory")
-
-	records = data.DefenderTable()
-	writeData(records, data.OutputFileName, "defender")
-
-	records = data.DefenderRecommendationsTable()
-	writeData(records, data.OutputFileName, "defenderRecommendations")
-
-	records = data.AdvisorTable()
-	writeData(records, data.OutputFileName, "advisor")
-
-	records = data.CostTable()
-	writeData(records, data.OutputFileName, "costs")
-
-	records = data.ExcludedResourcesTable()
-	writeData(records, data.OutputFileName, "outofscope")
+// CreateCsvReport renders each report table as a csv file
+func CreateCsvReport(data *renderers.ReportData) error {
+	for _, t := range data.Tables() {
+		if err := writeData(t.Records, data.OutputFileName, t.Name); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func writeData(data [][]string, fileName, extension string) {
+func writeData(data [][]string, fileName, extension string) error {
 	filename := fmt.Sprintf("%s.%s.csv", fileName, extension)
 	log.Info().Msgf("Generating Report: %s", filename)
 
 	f, err := os.Create(filename)
 	if err != nil {
-		log.Fatal().Err(err).Msg("error creating csv:")
+		return fmt.Errorf("error creating csv: %w", err)
 	}
+	defer f.Close()
 
 	w := csv.NewWriter(f)
 	err = w.WriteAll(data) // calls Flush internally
 
 	if err != nil {
-		log.Fatal().Err(w.Error()).Msg("error writing csv:")
+		return fmt.Errorf("error writing csv: %w", err)
 	}
+	return nil
 }
@@ -4,6 +4,7 @@
 package excel
 
 import (
+	"fmt"
 	_ "image/png"
 
 	"github.com/Azure/azqr/internal/renderers"
@@ -11,15 +12,17 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func renderAdvisor(f *excelize.File, data *renderers.ReportData) {
+func renderAdvisor(f *excelize.File, data *renderers.ReportData) error {
 	_, err := f.NewSheet("Advisor")
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create Advisor sheet")
+		return fmt.Errorf("failed to create Advisor sheet: %w", err)
 	}
 
 	records := data.AdvisorTable()
 	headers := records[0]
-	createFirstRow(f, "Advisor", headers)
+	if err := createFirstRow(f, "Advisor", headers); err != nil {
+		return err
+	}
 
 	if len(data.Advisor) > 0 {
 		records = records[1:]
@@ -28,16 +31,19 @@ func renderAdvisor(f *excelize.File, data *renderers.ReportData) {
 			currentRow += 1
 			cell, err := excelize.CoordinatesToCellName(1, currentRow)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to get cell")
+				return fmt.Errorf("failed to get cell: %w", err)
 			}
 			err = f.SetSheetRow("Advisor", cell, &row)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to set row")
+				return fmt.Errorf("failed to set row: %w", err)
 			}
 		}
 
-		configureSheet(f, "Advisor", headers, currentRow)
+		if err := configureSheet(f, "Advisor", headers, currentRow); err != nil {
+			return err
+		}
 	} else {
 		log.Info().Msg("Skipping Advisor. No data to render")
 	}
+	return nil
 }
@@ -4,6 +4,7 @@
 package excel
 
 import (
+	"fmt"
 	_ "image/png"
 
 	"github.com/Azure/azqr/internal/renderers"
@@ -11,15 +12,17 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func renderDefender(f *excelize.File, data *renderers.ReportData) {
+func renderDefender(f *excelize.File, data *renderers.ReportData) error {
 	_, err := f.NewSheet("Defender")
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create Defender sheet")
+		return fmt.Errorf("failed to create Defender sheet: %w", err)
 	}
 
 	records := data.DefenderTable()
 	headers := records[0]
-	createFirstRow(f, "Defender", headers)
+	if err := createFirstRow(f, "Defender", headers); err != nil {
+		return err
+	}
 
 	if len(data.Defender) > 0 {
 		records = records[1:]
@@ -28,31 +31,36 @@ func renderDefender(f *excelize.File, data *renderers.ReportData) {
 			currentRow += 1
 			cell, err := excelize.CoordinatesToCellName(1, currentRow)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to get cell")
+				return fmt.Errorf("failed to get cell: %w", err)
 			}
 			err = f.SetSheetRow("Defender", cell, &row)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to set row")
+				return fmt.Errorf("failed to set row: %w", err)
 			}
 		}
 
-		configureSheet(f, "Defender", headers, currentRow)
+		if err := configureSheet(f, "Defender", headers, currentRow); err != nil {
+			return err
+		}
 	} else {
 		log.Info().Msg("Skipping Defender. No data to render")
 	}
+	return nil
 }
 
 // renderDefenderRecommendations renders the Defender recommendations to the Excel sheet.
-func renderDefenderRecommendations(f *excelize.File, data *renderers.ReportData) {
+func renderDefenderRecommendations(f *excelize.File, data *renderers.ReportData) error {
 	sheetName := "DefenderRecommendations"
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create DefenderRecommendations sheet")
+		return fmt.Errorf("failed to create DefenderRecommendations sheet: %w", err)
 	}
 
 	records := data.DefenderRecommendationsTable()
 	headers := records[0]
-	createFirstRow(f, sheetName, headers)
+	if err := createFirstRow(f, sheetName, headers); err != nil {
+		return err
+	}
 
 	if len(data.DefenderRecommendations) > 0 {
 		records = records[1:]
@@ -61,17 +69,20 @@ func renderDefenderRecommendations(f *excelize.File, data *renderers.ReportData)
 			currentRow += 1
 			cell, err := excelize.CoordinatesToCellName(1, currentRow)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to get cell")
+				return fmt.Errorf("failed to get cell: %w", err)
 			}
 			err = f.SetSheetRow(sheetName, cell, &row)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to set row")
+				return fmt.Errorf("failed to set row: %w", err)
 			}
 			setHyperLink(f, sheetName, 11, currentRow)
 		}
 
-		configureSheet(f, sheetName, headers, currentRow)
+		if err := configureSheet(f, sheetName, headers, currentRow); err != nil {
+			return err
+		}
 	} else {
 		log.Info().Msg("Skipping DefenderRecommendations. No data to render")
 	}
+	return nil
 }
@@ -13,16 +13,18 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func renderRecommendations(f *excelize.File, data *renderers.ReportData) int {
+func renderRecommendations(f *excelize.File, data *renderers.ReportData) (int, error) {
 	sheetName := "Recommendations"
 	err := f.SetSheetName("Sheet1", sheetName)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Failed to create %s sheet", sheetName)
+		return 0, fmt.Errorf("failed to create %s sheet: %w", sheetName, err)
 	}
 
 	records := data.RecommendationsTable()
 	headers := records[0]
-	createFirstRow(f, sheetName, headers)
+	if err := createFirstRow(f, sheetName, headers); err != nil {
+		return 0, err
+	}
 
 	if len(data.Recommendations) > 0 {
 		records = records[1:]
@@ -31,29 +33,31 @@ func renderRecommendations(f *excelize.File, data *renderers.ReportData) int {
 			currentRow += 1
 			cell, err := excelize.CoordinatesToCellName(1, currentRow)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to get cell")
+				return 0, fmt.Errorf("failed to get cell: %w", err)
 			}
 			err = f.SetSheetRow(sheetName, cell, &row)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to set row")
+				return 0, fmt.Errorf("failed to set row: %w", err)
 			}
 			setHyperLink(f, sheetName, 11, currentRow)
 		}
 
-		configureSheet(f, sheetName, headers, currentRow)
-		return currentRow
+		if err := configureSheet(f, sheetName, headers, currentRow); err != nil {
+			return 0, err
+		}
+		return currentRow, nil
 	} else {
 		log.Info().Msgf("Skipping %s. No data to render", sheetName)
-		return 0
+		return 0, nil
 	}
 }
 
-func renderRecommendationsPivotTables(f *excelize.File, lastRow int) {
+func renderRecommendationsPivotTables(f *excelize.File, lastRow int) error {
 	sheetName := "PivotTable"
 	if lastRow > 0 {
 		_, err := f.NewSheet(sheetName)
 		if err != nil {
-			log.Fatal().Err(err).Msgf("Failed to create %s sheet", sheetName)
+			return fmt.Errorf("failed to create %s sheet: %w", sheetName, err)
 		}
 
 		if err := f.AddPivotTable(&excelize.PivotTableOptions{
@@ -75,7 +79,7 @@ func renderRecommendationsPivotTables(f *excelize.File, lastRow int) {
 			ShowLastColumn: true,
 		}); err != nil {
 			log.Info().Err(err).Msgf("Failed to create %s pivot table", sheetName)
-			return
+			return nil
 		}
 
 		if err := f.AddPivotTable(&excelize.PivotTableOptions{
@@ -97,9 +101,10 @@ func renderRecommendationsPivotTables(f *excelize.File, lastRow int) {
 			ShowLastColumn: true,
 		}); err != nil {
 			log.Info().Err(err).Msgf("Failed to create %s pivot table", sheetName)
-			return
+			return nil
 		}
 	} else {
 		log.Info().Msgf("Skipping %s. No data to render", sheetName)
 	}
+	return nil
 }
@@ -4,6 +4,7 @@
 package excel
 
 import (
+	"fmt"
 	_ "image/png"
 
 	"github.com/Azure/azqr/internal/renderers"
@@ -11,16 +12,18 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func renderResourceTypes(f *excelize.File, data *renderers.ReportData) {
+func renderResourceTypes(f *excelize.File, data *renderers.ReportData) error {
 	sheetName := "ResourceTypes"
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Failed to create %s sheet", sheetName)
+		return fmt.Errorf("failed to create %s sheet: %w", sheetName, err)
 	}
 
 	records := data.ResourceTypesTable()
 	headers := records[0]
-	createFirstRow(f, sheetName, headers)
+	if err := createFirstRow(f, sheetName, headers); err != nil {
+		return err
+	}
 
 	if len(data.ResourceTypeCount) > 0 {
 		records = records[1:]
@@ -30,17 +33,20 @@ func renderResourceTypes(f *excelize.File, data *renderers.ReportData) {
 			currentRow += 1
 			cell, err := excelize.CoordinatesToCellName(1, currentRow)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to get cell")
+				return fmt.Errorf("failed to get cell: %w", err)
 			}
 			err = f.SetSheetRow(sheetName, cell, &row)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to set row")
+				return fmt.Errorf("failed to set row: %w", err)
 			}
 			// setHyperLink(f, sheetName, 12, currentRow)
 		}
 
-		configureSheet(f, sheetName, headers, currentRow)
+		if err := configureSheet(f, sheetName, headers, currentRow); err != nil {
+			return err
+		}
 	} else {
 		log.Info().Msgf("Skipping %s. No data to render", sheetName)
 	}
+	return nil
 }
@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package excel
+
+import (
+	"fmt"
+	_ "image/png"
+
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/rs/zerolog/log"
+	"github.com/xuri/excelize/v2"
+)
+
+// renderSuppressed renders the findings suppressed as accepted risks, with their justification
+func renderSuppressed(f *excelize.File, data *renderers.ReportData) error {
+	sheetName := "Suppressed"
+	_, err := f.NewSheet(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to create Suppressed sheet: %w", err)
+	}
+
+	records := data.SuppressedTable()
+	headers := records[0]
+	if err := createFirstRow(f, sheetName, headers); err != nil {
+		return err
+	}
+
+	if len(data.Suppressed) > 0 {
+		records = records[1:]
+		currentRow := 4
+		for _, row := range records {
+			currentRow += 1
+			cell, err := excelize.CoordinatesToCellName(1, currentRow)
+			if err != nil {
+				return fmt.Errorf("failed to get cell: %w", err)
+			}
+			err = f.SetSheetRow(sheetName, cell, &row)
+			if err != nil {
+				return fmt.Errorf("failed to set row: %w", err)
+			}
+		}
+
+		if err := configureSheet(f, sheetName, headers, currentRow); err != nil {
+			return err
+		}
+	} else {
+		log.Info().Msg("Skipping Suppressed. No suppressed findings to render")
+	}
+	return nil
+}
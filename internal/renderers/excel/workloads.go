@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package excel
+
+import (
+	"fmt"
+	_ "image/png"
+
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/rs/zerolog/log"
+	"github.com/xuri/excelize/v2"
+)
+
+// renderWorkloadSLAs renders the components of the workloads with their SLA and the composite SLA of the workload
+func renderWorkloadSLAs(f *excelize.File, data *renderers.ReportData) error {
+	sheetName := "WorkloadSLA"
+	_, err := f.NewSheet(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to create WorkloadSLA sheet: %w", err)
+	}
+
+	records := data.WorkloadSLATable()
+	headers := records[0]
+	if err := createFirstRow(f, sheetName, headers); err != nil {
+		return err
+	}
+
+	if len(data.WorkloadSLAs) > 0 {
+		records = records[1:]
+		currentRow := 4
+		for _, row := range records {
+			currentRow += 1
+			cell, err := excelize.CoordinatesToCellName(1, currentRow)
+			if err != nil {
+				return fmt.Errorf("failed to get cell: %w", err)
+			}
+			err = f.SetSheetRow(sheetName, cell, &row)
+			if err != nil {
+				return fmt.Errorf("failed to set row: %w", err)
+			}
+		}
+
+		if err := configureSheet(f, sheetName, headers, currentRow); err != nil {
+			return err
+		}
+	} else {
+		log.Info().Msg("Skipping WorkloadSLA. No workloads to render")
+	}
+	return nil
+}
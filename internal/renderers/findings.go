@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+// Scanners of the findings
+const (
+	FindingScannerAprl = "APRL"
+	FindingScannerAzqr = "AZQR"
+)
+
+// Finding - Resource impacted by a recommendation. Subscription ids are not masked
+type Finding struct {
+	// Scanner is FindingScannerAprl or FindingScannerAzqr
+	Scanner          string `json:",omitempty"`
+	RecommendationID string
+	Recommendation   string
+	Source           string
+	Category         string
+	Impact           string
+	ResourceType     string
+	SubscriptionID   string
+	SubscriptionName string
+	ResourceGroup    string
+	ResourceName     string
+	ResourceID       string
+	Result           string
+	Learn            string
+	TenantID         string
+}
+
+// FindingsFile - Findings of a scan and the time the scan started
+type FindingsFile struct {
+	ScannedAt time.Time
+	Findings  []Finding
+}
+
+// Key identifies the finding across scans
+func (f Finding) Key() string {
+	return f.RecommendationID + "|" + strings.ToLower(f.ResourceID)
+}
+
+// NewFindings returns the resources impacted by the APRL recommendations and by the AZQR recommendations they do not comply with
+func NewFindings(aprl []scanners.AprlResult, azqr []scanners.AzqrServiceResult) []Finding {
+	findings := []Finding{}
+	for _, r := range aprl {
+		findings = append(findings, Finding{
+			Scanner:          FindingScannerAprl,
+			RecommendationID: r.RecommendationID,
+			Recommendation:   r.Recommendation,
+			Source:           r.Source,
+			Category:         string(r.Category),
+			Impact:           string(r.Impact),
+			ResourceType:     r.ResourceType,
+			SubscriptionID:   r.SubscriptionID,
+			SubscriptionName: r.SubscriptionName,
+			ResourceGroup:    r.ResourceGroup,
+			ResourceName:     r.Name,
+			ResourceID:       r.ResourceID,
+			Result:           r.Param1,
+			Learn:            r.Learn,
+			TenantID:         r.TenantID,
+		})
+	}
+
+	for _, d := range azqr {
+		for _, r := range d.Recommendations {
+			if !r.NotCompliant {
+				continue
+			}
+			source := r.Source
+			if source == "" {
+				source = "AZQR"
+			}
+			findings = append(findings, Finding{
+				Scanner:          FindingScannerAzqr,
+				RecommendationID: r.RecommendationID,
+				Recommendation:   r.Recommendation,
+				Source:           source,
+				Category:         string(r.Category),
+				Impact:           string(r.Impact),
+				ResourceType:     d.Type,
+				SubscriptionID:   d.SubscriptionID,
+				SubscriptionName: d.SubscriptionName,
+				ResourceGroup:    d.ResourceGroup,
+				ResourceName:     d.ServiceName,
+				ResourceID:       d.ResourceID(),
+				Result:           r.Result,
+				Learn:            r.LearnMoreUrl,
+				TenantID:         d.TenantID,
+			})
+		}
+	}
+	return findings
+}
+
+// Findings returns the resources impacted by the recommendations
+func (rd *ReportData) Findings() []Finding {
+	return NewFindings(rd.Aprl, rd.Azqr)
+}
+
+// SaveFindings writes the findings and the time the scan started to a json file, to be used as the baseline
+// of a later scan or as the starting point of an incremental scan
+func SaveFindings(file string, scannedAt time.Time, findings []Finding) error {
+	content, err := json.MarshalIndent(FindingsFile{ScannedAt: scannedAt, Findings: findings}, "", "\t")
+	if err != nil {
+		return fmt.Errorf("error marshaling findings: %w", err)
+	}
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		return fmt.Errorf("error writing findings: %w", err)
+	}
+	return nil
+}
+
+// LoadFindings reads the findings of a json file written by SaveFindings
+func LoadFindings(file string) ([]Finding, error) {
+	f, err := LoadFindingsFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return f.Findings, nil
+}
+
+// LoadFindingsFile reads a json file written by SaveFindings. The files of older versions only have the findings,
+// and their scan time is zero
+func LoadFindingsFile(file string) (*FindingsFile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading findings: %s: %w", file, err)
+	}
+
+	f := &FindingsFile{Findings: []Finding{}}
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "[") {
+		err = json.Unmarshal(content, &f.Findings)
+	} else {
+		err = json.Unmarshal(content, f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing findings: %s: %w", file, err)
+	}
+	if f.Findings == nil {
+		f.Findings = []Finding{}
+	}
+	return f, nil
+}
+
+// NewFindings returns the findings that are not in the baseline. All findings are new without a baseline
+func (rd *ReportData) NewFindings() []Finding {
+	return diffFindings(rd.Findings(), rd.Baseline)
+}
+
+// ResolvedFindings returns the findings of the baseline that are no longer found
+func (rd *ReportData) ResolvedFindings() []Finding {
+	if rd.Baseline == nil {
+		return []Finding{}
+	}
+	return diffFindings(rd.Baseline, rd.Findings())
+}
+
+// diffFindings returns the findings of a that are not in b
+func diffFindings(a, b []Finding) []Finding {
+	keys := map[string]bool{}
+	for _, f := range b {
+		keys[f.Key()] = true
+	}
+
+	result := []Finding{}
+	for _, f := range a {
+		if !keys[f.Key()] {
+			result = append(result, f)
+		}
+	}
+	return result
+}
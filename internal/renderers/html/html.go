@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package html
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+//go:embed report.html
+var reportTemplate string
+
+type (
+	// report - Data of the HTML report template
+	report struct {
+		Generated     string
+		Subscriptions int
+		Resources     int
+		Findings      int
+		High          int
+		Impacts       []bar
+		Categories    []bar
+		Tables        []table
+	}
+
+	// table - Report table rendered as a tab with client-side sorting and filtering
+	table struct {
+		ID      string
+		Title   string
+		Headers []string
+		Rows    [][]string
+	}
+
+	// bar - Bar of a chart
+	bar struct {
+		Label   string
+		Count   int
+		Percent int
+	}
+)
+
+// CreateHTMLReport renders the report tables as a single, self-contained HTML file
+func CreateHTMLReport(data *renderers.ReportData) error {
+	filename := fmt.Sprintf("%s.html", data.OutputFileName)
+	log.Info().Msgf("Generating Report: %s", filename)
+
+	t, err := template.New("report").Funcs(template.FuncMap{
+		"isLink": func(value string) bool {
+			return strings.HasPrefix(value, "https://")
+		},
+	}).Parse(reportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse html template: %w", err)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating html: %w", err)
+	}
+	defer f.Close()
+
+	if err := t.Execute(f, newReport(data)); err != nil {
+		return fmt.Errorf("error writing html: %w", err)
+	}
+	return nil
+}
+
+func newReport(data *renderers.ReportData) report {
+	tables := []table{}
+	var findings [][]string
+	for _, t := range data.Tables() {
+		if t.Name == "impacted" {
+			findings = t.Records
+		}
+		tables = append(tables, newTable(t.Name, t.Title, t.Records))
+	}
+
+	subscriptions := map[string]bool{}
+	for _, r := range data.Resources {
+		subscriptions[r.SubscriptionID] = true
+	}
+
+	r := report{
+		Generated:     time.Now().Format("2006-01-02 15:04"),
+		Subscriptions: len(subscriptions),
+		Resources:     len(data.Resources),
+		Findings:      len(findings) - 1,
+		Impacts:       bars(findings, "Impact", []string{"High", "Medium", "Low"}),
+		Categories:    bars(findings, "Category", nil),
+		Tables:        tables,
+	}
+	for _, b := range r.Impacts {
+		if b.Label == "High" {
+			r.High = b.Count
+		}
+	}
+	return r
+}
+
+func newTable(id, title string, records [][]string) table {
+	return table{
+		ID:      id,
+		Title:   title,
+		Headers: records[0],
+		Rows:    records[1:],
+	}
+}
+
+// bars counts the rows per value of a column. The bars follow the order of the labels,
+// or are sorted by count when no labels are given
+func bars(records [][]string, column string, labels []string) []bar {
+	index := -1
+	for i, h := range records[0] {
+		if h == column {
+			index = i
+		}
+	}
+	if index < 0 {
+		return []bar{}
+	}
+
+	counts := map[string]int{}
+	for _, row := range records[1:] {
+		counts[row[index]]++
+	}
+
+	if labels == nil {
+		for label := range counts {
+			labels = append(labels, label)
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			if counts[labels[i]] != counts[labels[j]] {
+				return counts[labels[i]] > counts[labels[j]]
+			}
+			return labels[i] < labels[j]
+		})
+	}
+
+	highest := 0
+	for _, c := range counts {
+		if c > highest {
+			highest = c
+		}
+	}
+
+	result := []bar{}
+	for _, label := range labels {
+		b := bar{Label: label, Count: counts[label]}
+		if highest > 0 {
+			b.Percent = b.Count * 100 / highest
+		}
+		result = append(result, b)
+	}
+	return result
+}
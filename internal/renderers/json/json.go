@@ -11,25 +11,26 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func CreateJsonReport(data *renderers.ReportData) {
-	writeData(data.RecommendationsTable(), data.OutputFileName, "recommendations")
-	writeData(data.ImpactedTable(), data.OutputFileName, "impacted")
-	writeData(data.ResourceTypesTable(), data.OutputFileName, "resourceType")
-	writeData(data.ResourcesTable(), data.OutputFileName, "inventory")
-	writeData(data.DefenderTable(), data.OutputFileName, "defender")
-	writeData(data.DefenderRecommendationsTable(), data.OutputFileName, "defenderRecommendations")
-	writeData(data.AdvisorTable(), data.OutputFileName, "advisor")
-	writeData(data.CostTable(), data.OutputFileName, "costs")
-	writeData(data.ExcludedResourcesTable(), data.OutputFileName, "outofscope")
+// CreateJsonReport renders each report table as a json file, and the findings as the baseline of a later scan
+func CreateJsonReport(data *renderers.ReportData) error {
+	for _, t := range data.Tables() {
+		if err := writeData(t.Records, data.OutputFileName, t.Name); err != nil {
+			return err
+		}
+	}
+
+	filename := fmt.Sprintf("%s.findings.json", data.OutputFileName)
+	log.Info().Msgf("Generating Report: %s", filename)
+	return renderers.SaveFindings(filename, data.ScannedAt, data.Findings())
 }
 
-func writeData(data [][]string, fileName, extension string) {
+func writeData(data [][]string, fileName, extension string) error {
 	filename := fmt.Sprintf("%s.%s.json", fileName, extension)
 	log.Info().Msgf("Generating Report: %s", filename)
 
 	f, err := os.Create(filename)
 	if err != nil {
-		log.Fatal().Err(err).Msg("error creating json:")
+		return fmt.Errorf("error creating json: %w", err)
 	}
 	defer f.Close()
 
@@ -37,13 +38,14 @@ func writeData(data [][]string, fileName, extension string) {
 
 	js, err := json.MarshalIndent(jsonData, "", "\t")
 	if err != nil {
-		log.Fatal().Err(err).Msg("error marshaling data:")
+		return fmt.Errorf("error marshaling data: %w", err)
 	}
 
 	_, err = f.Write(js)
 	if err != nil {
-		log.Fatal().Err(err).Msg("error writing json:")
+		return fmt.Errorf("error writing json: %w", err)
 	}
+	return nil
 }
 
 func convertToJSON(data [][]string) []map[string]string {
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+type (
+	testSuites struct {
+		XMLName  xml.Name    `xml:"testsuites"`
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Suites   []testSuite `xml:"testsuite"`
+	}
+
+	// testSuite - Recommendations of a resource type
+	testSuite struct {
+		Name     string     `xml:"name,attr"`
+		Tests    int        `xml:"tests,attr"`
+		Failures int        `xml:"failures,attr"`
+		Cases    []testCase `xml:"testcase"`
+	}
+
+	// testCase - Recommendation, failed by each impacted resource
+	testCase struct {
+		Name      string    `xml:"name,attr"`
+		ClassName string    `xml:"classname,attr"`
+		Failures  []failure `xml:"failure"`
+	}
+
+	failure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// CreateJunitReport renders the recommendations as JUnit test cases, with a failure per impacted resource
+func CreateJunitReport(data *renderers.ReportData) error {
+	filename := fmt.Sprintf("%s.junit.xml", data.OutputFileName)
+	log.Info().Msgf("Generating Report: %s", filename)
+
+	content, err := xml.MarshalIndent(newTestSuites(data), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling junit: %w", err)
+	}
+
+	content = append([]byte(xml.Header), content...)
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return fmt.Errorf("error writing junit: %w", err)
+	}
+	return nil
+}
+
+func newTestSuites(data *renderers.ReportData) testSuites {
+	// test cases per resource type and recommendation id
+	cases := map[string]map[string]*testCase{}
+	add := func(resourceType, id, recommendation string) *testCase {
+		t := strings.ToLower(resourceType)
+		if cases[t] == nil {
+			cases[t] = map[string]*testCase{}
+		}
+		if cases[t][id] == nil {
+			cases[t][id] = &testCase{
+				Name:      fmt.Sprintf("%s: %s", id, recommendation),
+				ClassName: resourceType,
+				Failures:  []failure{},
+			}
+		}
+		return cases[t][id]
+	}
+
+	for _, rt := range data.Recommendations {
+		for id, r := range rt {
+			add(r.ResourceType, id, r.Recommendation)
+		}
+	}
+
+	for _, f := range data.Findings() {
+		c := add(f.ResourceType, f.RecommendationID, f.Recommendation)
+		text := fmt.Sprintf("Category: %s\nSource: %s\nResource: %s", f.Category, f.Source, renderers.MaskSubscriptionIDInResourceID(f.ResourceID, data.Mask))
+		if f.Result != "" {
+			text = fmt.Sprintf("%s\nResult: %s", text, f.Result)
+		}
+		if f.Learn != "" {
+			text = fmt.Sprintf("%s\nLearn: %s", text, f.Learn)
+		}
+		c.Failures = append(c.Failures, failure{
+			Message: fmt.Sprintf("%s is impacted", f.ResourceName),
+			Type:    f.Impact,
+			Text:    text,
+		})
+	}
+
+	types := make([]string, 0, len(cases))
+	for t := range cases {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	suites := testSuites{Name: "azqr", Suites: []testSuite{}}
+	for _, t := range types {
+		ids := make([]string, 0, len(cases[t]))
+		for id := range cases[t] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		suite := testSuite{Cases: []testCase{}}
+		for _, id := range ids {
+			c := cases[t][id]
+			sort.Slice(c.Failures, func(i, j int) bool {
+				return c.Failures[i].Text < c.Failures[j].Text
+			})
+			suite.Name = c.ClassName
+			suite.Tests++
+			if len(c.Failures) > 0 {
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, *c)
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
@@ -0,0 +1,260 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+const (
+	// maxLength keeps the summary below the 65536 characters of a GitHub pull request comment
+	maxLength = 60000
+	// maxResourceTypes is the number of top impacted resource types
+	maxResourceTypes = 10
+	// maxChanges is the number of new and resolved findings listed
+	maxChanges = 20
+	// reserved is the room kept below maxLength for the notes of the omitted content
+	reserved = 200
+)
+
+// impacts are the impacts of the recommendations, from the highest
+var impacts = []string{"High", "Medium", "Low"}
+
+// count - Number of findings of a value
+type count struct {
+	value string
+	count int
+}
+
+// CreateMarkdownReport renders a summary of the findings as a markdown file, sized for pull request comments and wiki pages
+func CreateMarkdownReport(data *renderers.ReportData) error {
+	filename := fmt.Sprintf("%s.md", data.OutputFileName)
+	log.Info().Msgf("Generating Report: %s", filename)
+
+	if err := os.WriteFile(filename, []byte(newSummary(data)), 0644); err != nil {
+		return fmt.Errorf("error writing markdown: %w", err)
+	}
+	return nil
+}
+
+func newSummary(data *renderers.ReportData) string {
+	findings := data.Findings()
+
+	subscriptions := map[string]bool{}
+	for _, r := range data.Resources {
+		subscriptions[r.SubscriptionID] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("# Azure Quick Review\n\n")
+	fmt.Fprintf(&b, "**%d** findings in **%d** resources of **%d** subscriptions", len(findings), len(data.Resources), len(subscriptions))
+	if len(data.Suppressed) > 0 {
+		fmt.Fprintf(&b, " (%d suppressed)", len(data.Suppressed))
+	}
+	b.WriteString(".\n")
+	if len(data.Errors) > 0 {
+		fmt.Fprintf(&b, "\n> [!WARNING]\n> The scan raised %d errors. The results are partial.\n", len(data.Errors))
+	}
+
+	byImpact := countBy(findings, func(f renderers.Finding) string { return f.Impact })
+	sort.SliceStable(byImpact, func(i, j int) bool {
+		return impactRank(byImpact[i].value) < impactRank(byImpact[j].value)
+	})
+	writeCounts(&b, "Impact", byImpact)
+	writeCounts(&b, "Category", countBy(findings, func(f renderers.Finding) string { return f.Category }))
+	writeCounts(&b, "Source", countBy(findings, func(f renderers.Finding) string { return f.Source }))
+
+	byType := countBy(findings, func(f renderers.Finding) string { return f.ResourceType })
+	top := byType
+	if len(top) > maxResourceTypes {
+		top = top[:maxResourceTypes]
+	}
+	writeCounts(&b, "Top impacted resource types", top)
+
+	if data.Baseline != nil {
+		writeChanges(&b, data.NewFindings(), data.ResolvedFindings())
+	}
+
+	// per service detail, until the summary is too long
+	details := []string{}
+	for _, t := range byType {
+		details = append(details, serviceDetail(t.value, findings))
+	}
+	if len(details) > 0 && fits(&b, "\n## Details\n") {
+		b.WriteString("\n## Details\n")
+	}
+	for i, d := range details {
+		if !fits(&b, d) {
+			writeNote(&b, fmt.Sprintf("\n_The details of %d resource types are omitted. See the full report._\n", len(details)-i))
+			break
+		}
+		b.WriteString(d)
+	}
+	return b.String()
+}
+
+// fits returns true when the text can be added to the summary, keeping room for a note of the omitted content
+func fits(b *strings.Builder, text string) bool {
+	return b.Len()+len(text)+reserved <= maxLength
+}
+
+// writeNote adds a note of the omitted content, unless the summary would be longer than maxLength
+func writeNote(b *strings.Builder, note string) {
+	if b.Len()+len(note) <= maxLength {
+		b.WriteString(note)
+	}
+}
+
+// writeChanges writes the number of new and resolved findings, with the first findings of each
+func writeChanges(b *strings.Builder, added, resolved []renderers.Finding) {
+	summary := fmt.Sprintf("\n## Changes since the baseline\n\n**%d** new findings, **%d** resolved findings.\n", len(added), len(resolved))
+	if !fits(b, summary) {
+		return
+	}
+	b.WriteString(summary)
+	for _, c := range []struct {
+		title    string
+		findings []renderers.Finding
+	}{
+		{"New findings", added},
+		{"Resolved findings", resolved},
+	} {
+		if len(c.findings) == 0 {
+			continue
+		}
+
+		sortFindings(c.findings)
+		header := fmt.Sprintf("\n### %s\n\nImpact | Id | Recommendation | Resource | Resource Group\n---|---|---|---|---\n", c.title)
+		if !fits(b, header) {
+			writeNote(b, fmt.Sprintf("\n_The %d %s are omitted. See the full report._\n", len(c.findings), strings.ToLower(c.title)))
+			continue
+		}
+		b.WriteString(header)
+		for i, f := range c.findings {
+			row := fmt.Sprintf("%s | %s | %s | %s | %s\n", f.Impact, f.RecommendationID, cell(f.Recommendation), cell(f.ResourceName), cell(f.ResourceGroup))
+			if i == maxChanges || !fits(b, row) {
+				writeNote(b, fmt.Sprintf("\n_And %d more._\n", len(c.findings)-i))
+				break
+			}
+			b.WriteString(row)
+		}
+	}
+}
+
+// serviceDetail returns a collapsible section with the recommendations impacting a resource type
+func serviceDetail(resourceType string, findings []renderers.Finding) string {
+	type recommendation struct {
+		renderers.Finding
+		count int
+	}
+
+	recommendations := map[string]*recommendation{}
+	total := 0
+	for _, f := range findings {
+		if f.ResourceType != resourceType {
+			continue
+		}
+		total++
+		if r, ok := recommendations[f.RecommendationID]; ok {
+			r.count++
+			continue
+		}
+		recommendations[f.RecommendationID] = &recommendation{Finding: f, count: 1}
+	}
+
+	sorted := make([]*recommendation, 0, len(recommendations))
+	for _, r := range recommendations {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if impactRank(sorted[i].Impact) != impactRank(sorted[j].Impact) {
+			return impactRank(sorted[i].Impact) < impactRank(sorted[j].Impact)
+		}
+		return sorted[i].RecommendationID < sorted[j].RecommendationID
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n<details>\n<summary>%s (%d)</summary>\n\n", resourceType, total)
+	b.WriteString("Impact | Id | Category | Recommendation | Resources\n")
+	b.WriteString("---|---|---|---|---\n")
+	for _, r := range sorted {
+		id := r.RecommendationID
+		if r.Learn != "" {
+			id = fmt.Sprintf("[%s](%s)", id, r.Learn)
+		}
+		fmt.Fprintf(&b, "%s | %s | %s | %s | %d\n", r.Impact, id, r.Category, cell(r.Recommendation), r.count)
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
+
+func writeCounts(b *strings.Builder, title string, counts []count) {
+	if len(counts) == 0 {
+		return
+	}
+	header := fmt.Sprintf("\n%s | Findings\n---|---\n", title)
+	if !fits(b, header) {
+		return
+	}
+	b.WriteString(header)
+	for i, c := range counts {
+		row := fmt.Sprintf("%s | %d\n", cell(c.value), c.count)
+		if !fits(b, row) {
+			writeNote(b, fmt.Sprintf("\n_And %d more._\n", len(counts)-i))
+			return
+		}
+		b.WriteString(row)
+	}
+}
+
+// countBy counts the findings per value, sorted by count
+func countBy(findings []renderers.Finding, value func(renderers.Finding) string) []count {
+	counts := map[string]int{}
+	for _, f := range findings {
+		counts[value(f)]++
+	}
+
+	result := make([]count, 0, len(counts))
+	for v, c := range counts {
+		result = append(result, count{value: v, count: c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].count != result[j].count {
+			return result[i].count > result[j].count
+		}
+		return result[i].value < result[j].value
+	})
+	return result
+}
+
+func sortFindings(findings []renderers.Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if impactRank(findings[i].Impact) != impactRank(findings[j].Impact) {
+			return impactRank(findings[i].Impact) < impactRank(findings[j].Impact)
+		}
+		return findings[i].Key() < findings[j].Key()
+	})
+}
+
+func impactRank(impact string) int {
+	for i, v := range impacts {
+		if strings.EqualFold(v, impact) {
+			return i
+		}
+	}
+	return len(impacts)
+}
+
+// cell escapes a value for a markdown table cell
+func cell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.Join(strings.Fields(value), " ")
+}
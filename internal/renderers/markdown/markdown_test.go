@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+// newReportData returns a report with a not compliant result per resource, and a baseline with a finding
+// of the first resource and a resolved finding
+func newReportData(resources int, recommendation string) *renderers.ReportData {
+	rd := renderers.NewReportData("report", false)
+	for i := 0; i < resources; i++ {
+		rd.Azqr = append(rd.Azqr, scanners.AzqrServiceResult{
+			SubscriptionID: "0000",
+			ResourceGroup:  "rg",
+			Type:           fmt.Sprintf("Microsoft.Test/type%d", i),
+			ServiceName:    fmt.Sprintf("res%d", i),
+			Recommendations: map[string]scanners.AzqrResult{
+				"test-001": {RecommendationID: "test-001", Recommendation: recommendation, Impact: scanners.ImpactHigh, NotCompliant: true},
+			},
+		})
+	}
+
+	rd.Baseline = renderers.NewFindings([]scanners.AprlResult{{
+		RecommendationID: "aprl-001",
+		Recommendation:   "Resolved",
+		Impact:           scanners.ImpactLow,
+		ResourceGroup:    "rg",
+		Name:             "old",
+		ResourceID:       "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Test/type0/old",
+	}}, rd.Azqr[:1])
+	return &rd
+}
+
+func TestNewSummary_Changes(t *testing.T) {
+	summary := newSummary(newReportData(2, "Test recommendation"))
+
+	for _, expected := range []string{
+		"**1** new findings, **1** resolved findings.",
+		"### New findings\n\nImpact | Id | Recommendation | Resource | Resource Group\n---|---|---|---|---\nHigh | test-001 | Test recommendation | res1 | rg\n",
+		"### Resolved findings\n\nImpact | Id | Recommendation | Resource | Resource Group\n---|---|---|---|---\nLow | aprl-001 | Resolved | old | rg\n",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("expected %q in the summary:\n%s", expected, summary)
+		}
+	}
+}
+
+func TestNewSummary_MaxLength(t *testing.T) {
+	summary := newSummary(newReportData(500, strings.Repeat("Long recommendation ", 200)))
+
+	if len(summary) > maxLength {
+		t.Errorf("expected at most %d characters, got %d", maxLength, len(summary))
+	}
+	for _, expected := range []string{
+		"**499** new findings",
+		"### New findings",
+		"_And 485 more._",
+		"Low | aprl-001 | Resolved | old | rg\n",
+		"_The details of 500 resource types are omitted. See the full report._",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("expected %q in the summary", expected)
+		}
+	}
+}
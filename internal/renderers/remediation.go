@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// RemediationScript - Consolidated remediation script of a subscription
+	RemediationScript struct {
+		SubscriptionID   string
+		SubscriptionName string
+		Content          string
+	}
+
+	// remediationFinding - Finding with a rendered remediation
+	remediationFinding struct {
+		recommendationID string
+		recommendation   string
+		resourceID       string
+		remediation      *scanners.Remediation
+	}
+)
+
+// RemediationScripts returns a script per subscription with the fixes of its findings.
+// The az CLI commands are run by the script, the Bicep and Terraform fixes are added as comments
+func (rd *ReportData) RemediationScripts() []RemediationScript {
+	findings := map[string][]remediationFinding{}
+	names := map[string]string{}
+
+	add := func(subscriptionID, subscriptionName, recommendationID, recommendation string, remediation *scanners.Remediation, target scanners.RemediationTarget) {
+		rendered := renderRemediation(recommendationID, remediation, target)
+		if rendered == nil {
+			return
+		}
+		names[subscriptionID] = subscriptionName
+		findings[subscriptionID] = append(findings[subscriptionID], remediationFinding{
+			recommendationID: recommendationID,
+			recommendation:   recommendation,
+			resourceID:       target.ResourceID,
+			remediation:      rendered,
+		})
+	}
+
+	for _, r := range rd.Aprl {
+		add(r.SubscriptionID, r.SubscriptionName, r.RecommendationID, r.Recommendation, r.Remediation, r.RemediationTarget())
+	}
+	for _, d := range rd.Azqr {
+		for _, r := range d.Recommendations {
+			if r.NotCompliant {
+				add(d.SubscriptionID, d.SubscriptionName, r.RecommendationID, r.Recommendation, r.Remediation, d.RemediationTarget())
+			}
+		}
+	}
+
+	scripts := []RemediationScript{}
+	for subscriptionID, f := range findings {
+		sort.Slice(f, func(i, j int) bool {
+			if f[i].recommendationID != f[j].recommendationID {
+				return f[i].recommendationID < f[j].recommendationID
+			}
+			return f[i].resourceID < f[j].resourceID
+		})
+
+		var b strings.Builder
+		b.WriteString("#!/usr/bin/env bash\n")
+		fmt.Fprintf(&b, "# Remediation of the azqr findings of the subscription %s (%s)\n", names[subscriptionID], subscriptionID)
+		b.WriteString("# Review each fix before running this script\n")
+		b.WriteString("set -euo pipefail\n\n")
+		fmt.Fprintf(&b, "az account set --subscription %s\n", subscriptionID)
+
+		for _, finding := range f {
+			fmt.Fprintf(&b, "\n# %s: %s\n# %s\n", finding.recommendationID, finding.recommendation, finding.resourceID)
+			if finding.remediation.AzCli != "" {
+				b.WriteString(finding.remediation.AzCli + "\n")
+			}
+			if finding.remediation.Bicep != "" {
+				b.WriteString(comment("Bicep: "+finding.remediation.Bicep) + "\n")
+			}
+			if finding.remediation.Terraform != "" {
+				b.WriteString(comment("Terraform: "+finding.remediation.Terraform) + "\n")
+			}
+		}
+
+		scripts = append(scripts, RemediationScript{
+			SubscriptionID:   subscriptionID,
+			SubscriptionName: names[subscriptionID],
+			Content:          b.String(),
+		})
+	}
+
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].SubscriptionID < scripts[j].SubscriptionID
+	})
+	return scripts
+}
+
+// remediationText returns the fixes of a finding for the Remediation column, with the subscription id masked
+func (rd *ReportData) remediationText(recommendationID string, remediation *scanners.Remediation, target scanners.RemediationTarget) string {
+	rendered := renderRemediation(recommendationID, remediation, target)
+	if rendered == nil {
+		return ""
+	}
+
+	text := rendered.String()
+	if rd.Mask && target.SubscriptionID != "" {
+		text = strings.ReplaceAll(text, target.SubscriptionID, MaskSubscriptionID(target.SubscriptionID, rd.Mask))
+	}
+	return text
+}
+
+// renderRemediation renders the remediation templates of a finding. It returns nil when the finding has no remediation
+func renderRemediation(recommendationID string, remediation *scanners.Remediation, target scanners.RemediationTarget) *scanners.Remediation {
+	if remediation == nil {
+		return nil
+	}
+
+	rendered, err := remediation.Render(target)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Skipping the remediation of recommendation %s for %s", recommendationID, target.ResourceID)
+		return nil
+	}
+	return rendered
+}
+
+// comment prefixes each line with #
+func comment(text string) string {
+	return "# " + strings.ReplaceAll(text, "\n", "\n# ")
+}
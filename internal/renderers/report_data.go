@@ -5,15 +5,21 @@ package renderers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Azure/azqr/internal/scanners"
 )
 
 type (
 	ReportData struct {
-		OutputFileName          string
-		Mask                    bool
+		OutputFileName string
+		Mask           bool
+		// ScannedAt is the time the scan started
+		ScannedAt time.Time
+		// Remediation adds the Remediation column to the impacted resources
+		Remediation             bool
 		Azqr                    []scanners.AzqrServiceResult
 		Aprl                    []scanners.AprlResult
 		Defender                []scanners.DefenderResult
@@ -24,6 +30,20 @@ type (
 		Resources               []*scanners.Resource
 		ExludedResources        []*scanners.Resource
 		ResourceTypeCount       []scanners.ResourceTypeCount
+		Errors                  []scanners.ScanError
+		Suppressed              []scanners.SuppressedResult
+		WorkloadSLAs            []scanners.WorkloadSLA
+		// Baseline are the findings of a previous scan. Nil when the scan has no baseline
+		Baseline []Finding
+		Tenants  []string
+	}
+
+	// Table - Table of the report, rendered as a file or as a tab of the HTML report
+	Table struct {
+		// Name is the suffix of the file of the table
+		Name    string
+		Title   string
+		Records [][]string
 	}
 
 	ResourceTypeCountResults struct {
@@ -31,6 +51,24 @@ type (
 	}
 )
 
+// Tables returns the tables of the report, with their headers in the first row
+func (rd *ReportData) Tables() []Table {
+	return []Table{
+		{"recommendations", "Recommendations", rd.RecommendationsTable()},
+		{"impacted", "Impacted Resources", rd.ImpactedTable()},
+		{"resourceType", "Resource Types", rd.ResourceTypesTable()},
+		{"inventory", "Inventory", rd.ResourcesTable()},
+		{"defender", "Defender", rd.DefenderTable()},
+		{"defenderRecommendations", "Defender Recommendations", rd.DefenderRecommendationsTable()},
+		{"advisor", "Advisor", rd.AdvisorTable()},
+		{"costs", "Costs", rd.CostTable()},
+		{"outofscope", "Out of scope", rd.ExcludedResourcesTable()},
+		{"errors", "Scan Errors", rd.ErrorsTable()},
+		{"suppressed", "Suppressed", rd.SuppressedTable()},
+		{"workloads", "Workload SLAs", rd.WorkloadSLATable()},
+	}
+}
+
 func (rd *ReportData) ResourcesTable() [][]string {
 	return rd.resourcesTable(rd.Resources)
 }
@@ -40,7 +78,11 @@ func (rd *ReportData) ExcludedResourcesTable() [][]string {
 }
 
 func (rd *ReportData) ImpactedTable() [][]string {
-	headers := []string{"Validated Using", "Source", "Category", "Impact", "Resource Type", "Recommendation", "Recommendation Id", "Subscription Id", "Subscription Name", "Resource Group", "Resource Name", "Resource Id", "Param1", "Param2", "Param3", "Param4", "Param5", "Learn"}
+	headers := []string{"Validated Using", "Source", "Category", "Impact", "Resource Type", "Recommendation", "Recommendation Id", "Subscription Id", "Subscription Name", "Resource Group", "Resource Name", "Resource Id", "Param1", "Param2", "Param3", "Param4", "Param5", "Learn", "Status"}
+	if rd.Remediation {
+		headers = append(headers, "Remediation")
+	}
+	headers = rd.withTenant(headers, "Tenant")
 
 	rows := [][]string{}
 	for _, r := range rd.Aprl {
@@ -63,16 +105,25 @@ func (rd *ReportData) ImpactedTable() [][]string {
 			r.Param4,
 			r.Param5,
 			r.Learn,
+			string(scanners.StatusNotCompliant),
 		}
-		rows = append(rows, row)
+		if rd.Remediation {
+			row = append(row, rd.remediationText(r.RecommendationID, r.Remediation, r.RemediationTarget()))
+		}
+		rows = append(rows, rd.withTenant(row, r.TenantID))
 	}
 
+	// AZQR rows also list the resources a rule is not applicable to or failed to evaluate for
 	for _, d := range rd.Azqr {
 		for _, r := range d.Recommendations {
 			if r.NotCompliant {
+				source := r.Source
+				if source == "" {
+					source = "AZQR"
+				}
 				row := []string{
 					"Azure Resource Manager",
-					"AZQR",
+					source,
 					string(r.Category),
 					string(r.Impact),
 					d.Type,
@@ -89,8 +140,12 @@ func (rd *ReportData) ImpactedTable() [][]string {
 					"",
 					"",
 					r.LearnMoreUrl,
+					string(scanners.StatusNotCompliant),
 				}
-				rows = append(rows, row)
+				if rd.Remediation {
+					row = append(row, rd.remediationText(r.RecommendationID, r.Remediation, d.RemediationTarget()))
+				}
+				rows = append(rows, rd.withTenant(row, d.TenantID))
 			}
 		}
 	}
@@ -101,6 +156,7 @@ func (rd *ReportData) ImpactedTable() [][]string {
 
 func (rd *ReportData) CostTable() [][]string {
 	headers := []string{"From", "To", "Subscription Id", "Subscription Name", "Service Name", "Value", "Currency"}
+	headers = rd.withTenant(headers, "Tenant")
 
 	rows := [][]string{}
 	for _, r := range rd.Cost.Items {
@@ -113,7 +169,7 @@ func (rd *ReportData) CostTable() [][]string {
 			r.Value,
 			r.Currency,
 		}
-		rows = append(rows, row)
+		rows = append(rows, rd.withTenant(row, r.TenantID))
 	}
 
 	rows = append([][]string{headers}, rows...)
@@ -122,6 +178,7 @@ func (rd *ReportData) CostTable() [][]string {
 
 func (rd *ReportData) DefenderTable() [][]string {
 	headers := []string{"Subscription Id", "Subscription Name", "Name", "Tier"}
+	headers = rd.withTenant(headers, "Tenant")
 	rows := [][]string{}
 	for _, d := range rd.Defender {
 		row := []string{
@@ -130,7 +187,7 @@ func (rd *ReportData) DefenderTable() [][]string {
 			d.Name,
 			d.Tier,
 		}
-		rows = append(rows, row)
+		rows = append(rows, rd.withTenant(row, d.TenantID))
 	}
 
 	rows = append([][]string{headers}, rows...)
@@ -139,6 +196,7 @@ func (rd *ReportData) DefenderTable() [][]string {
 
 func (rd *ReportData) AdvisorTable() [][]string {
 	headers := []string{"Subscription Id", "Subscription Name", "Resource Type", "Resource Name", "Category", "Impact", "Description", "Resource Id", "Recommendation Id"}
+	headers = rd.withTenant(headers, "Tenant")
 	rows := [][]string{}
 	for _, d := range rd.Advisor {
 		row := []string{
@@ -152,63 +210,82 @@ func (rd *ReportData) AdvisorTable() [][]string {
 			MaskSubscriptionIDInResourceID(d.ResourceID, rd.Mask),
 			d.RecommendationID,
 		}
-		rows = append(rows, row)
+		rows = append(rows, rd.withTenant(row, d.TenantID))
 	}
 
 	rows = append([][]string{headers}, rows...)
 	return rows
 }
 
+// RecommendationsTable returns the recommendations with their number of impacted resources.
+// Reports of several tenants have a row per tenant and recommendation
 func (rd *ReportData) RecommendationsTable() [][]string {
-	counter := map[string]int{}
-	for _, rt := range rd.Recommendations {
-		for _, r := range rt {
-			counter[r.RecommendationID] = 0
-		}
+	// counters per tenant and recommendation id
+	key := func(tenantID, recommendationID string) string {
+		return tenantID + "|" + recommendationID
 	}
 
+	counter := map[string]int{}
 	for _, r := range rd.Aprl {
-		counter[r.RecommendationID]++
+		counter[key(r.TenantID, r.RecommendationID)]++
 	}
 
+	// recommendations that failed to evaluate for at least one resource
+	failed := map[string]bool{}
 	for _, d := range rd.Azqr {
 		for _, r := range d.Recommendations {
 			if r.NotCompliant {
-				counter[r.RecommendationID]++
+				counter[key(d.TenantID, r.RecommendationID)]++
+			} else if r.Status == scanners.StatusError {
+				failed[key(d.TenantID, r.RecommendationID)] = true
 			}
 		}
 	}
 
+	tenants := rd.Tenants
+	if len(tenants) == 0 {
+		tenants = []string{""}
+	}
+
 	headers := []string{"Implemented", "Number of Impacted Resources", "Azure Service / Well-Architected", "Recommendation Source",
 		"Azure Service Category / Well-Architected Area", "Azure Service / Well-Architected Topic", "Resiliency Category", "Recommendation",
 		"Impact", "Best Practices Guidance", "Read More", "Recommendation Id"}
+	headers = rd.withTenant(headers, "Tenant")
 	rows := [][]string{}
-	for _, rt := range rd.Recommendations {
-		for _, r := range rt {
-			implemented := counter[r.RecommendationID] == 0
-			categoryPart := ""
-			servicePart := ""
-			typeParts := strings.Split(r.ResourceType, "/")
-			categoryPart = typeParts[0]
-			if len(typeParts) > 1 {
-				servicePart = typeParts[1]
-			}
+	for _, tenantID := range tenants {
+		for _, rt := range rd.Recommendations {
+			for _, r := range rt {
+				count := counter[key(tenantID, r.RecommendationID)]
+				implemented := "true"
+				if count > 0 {
+					implemented = "false"
+				} else if failed[key(tenantID, r.RecommendationID)] {
+					implemented = "unknown"
+				}
+				categoryPart := ""
+				servicePart := ""
+				typeParts := strings.Split(r.ResourceType, "/")
+				categoryPart = typeParts[0]
+				if len(typeParts) > 1 {
+					servicePart = typeParts[1]
+				}
 
-			row := []string{
-				fmt.Sprintf("%t", implemented),
-				fmt.Sprint(counter[r.RecommendationID]),
-				"Azure Service",
-				r.Source,
-				categoryPart,
-				servicePart,
-				string(r.Category),
-				r.Recommendation,
-				string(r.Impact),
-				r.LongDescription,
-				r.LearnMoreLink[0].Url,
-				r.RecommendationID,
+				row := []string{
+					implemented,
+					fmt.Sprint(count),
+					"Azure Service",
+					r.Source,
+					categoryPart,
+					servicePart,
+					string(r.Category),
+					r.Recommendation,
+					string(r.Impact),
+					r.LongDescription,
+					r.LearnMoreLink[0].Url,
+					r.RecommendationID,
+				}
+				rows = append(rows, rd.withTenant(row, tenantID))
 			}
-			rows = append(rows, row)
 		}
 	}
 
@@ -218,6 +295,7 @@ func (rd *ReportData) RecommendationsTable() [][]string {
 
 func (rd *ReportData) ResourceTypesTable() [][]string {
 	headers := []string{"Subscription Name", "Resource Type", "Number of Resources", "Available in APRL?", "Custom1", "Custom2", "Custom3"}
+	headers = rd.withTenant(headers, "Tenant")
 	rows := [][]string{}
 	for _, r := range rd.ResourceTypeCount {
 		row := []string{
@@ -229,7 +307,7 @@ func (rd *ReportData) ResourceTypesTable() [][]string {
 			"",
 			"",
 		}
-		rows = append(rows, row)
+		rows = append(rows, rd.withTenant(row, r.TenantID))
 	}
 
 	rows = append([][]string{headers}, rows...)
@@ -238,6 +316,7 @@ func (rd *ReportData) ResourceTypesTable() [][]string {
 
 func (rd *ReportData) DefenderRecommendationsTable() [][]string {
 	headers := []string{"Subscription Id", "Subscription Name", "Resource Group", "Resource Type", "Resource Name", "Category", "Recommendation Severity", "Recommendation Name", "Action Description", "Remediation Description", "AzPortal Link", "Resource Id"}
+	headers = rd.withTenant(headers, "Tenant")
 	rows := [][]string{}
 	for _, d := range rd.DefenderRecommendations {
 		row := []string{
@@ -254,13 +333,192 @@ func (rd *ReportData) DefenderRecommendationsTable() [][]string {
 			d.AzPortalLink,
 			MaskSubscriptionIDInResourceID(d.ResourceId, rd.Mask),
 		}
-		rows = append(rows, row)
+		rows = append(rows, rd.withTenant(row, d.TenantID))
 	}
 
 	rows = append([][]string{headers}, rows...)
 	return rows
 }
 
+func (rd *ReportData) ErrorsTable() [][]string {
+	headers := []string{"Phase", "Subscription Id", "Subscription Name", "Scanner", "Error"}
+	headers = rd.withTenant(headers, "Tenant")
+	rows := [][]string{}
+	for _, e := range rd.Errors {
+		row := []string{
+			e.Phase,
+			MaskSubscriptionID(e.SubscriptionID, rd.Mask),
+			e.SubscriptionName,
+			e.Scanner,
+			e.Message,
+		}
+		rows = append(rows, rd.withTenant(row, e.TenantID))
+	}
+
+	// the rules that failed to evaluate for a resource
+	for _, d := range rd.Azqr {
+		ids := make([]string, 0, len(d.Recommendations))
+		for id, r := range d.Recommendations {
+			if r.Status == scanners.StatusError {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			r := d.Recommendations[id]
+			row := []string{
+				scanners.PhaseRule,
+				MaskSubscriptionID(d.SubscriptionID, rd.Mask),
+				d.SubscriptionName,
+				r.RecommendationID,
+				fmt.Sprintf("%s: %s", MaskSubscriptionIDInResourceID(d.ResourceID(), rd.Mask), r.Result),
+			}
+			rows = append(rows, rd.withTenant(row, d.TenantID))
+		}
+	}
+
+	rows = append([][]string{headers}, rows...)
+	return rows
+}
+
+func (rd *ReportData) SuppressedTable() [][]string {
+	headers := []string{"Source", "Recommendation Id", "Recommendation", "Resource Type", "Subscription Id", "Subscription Name", "Resource Group", "Resource Name", "Resource Id", "Justification", "Owner", "Approver", "Expires"}
+	headers = rd.withTenant(headers, "Tenant")
+	rows := [][]string{}
+	for _, r := range rd.Suppressed {
+		row := []string{
+			r.Source,
+			r.RecommendationID,
+			r.Recommendation,
+			r.ResourceType,
+			MaskSubscriptionID(r.SubscriptionID, rd.Mask),
+			r.SubscriptionName,
+			r.ResourceGroup,
+			r.Name,
+			MaskSubscriptionIDInResourceID(r.ResourceID, rd.Mask),
+			r.Justification,
+			r.Owner,
+			r.Approver,
+			r.Expires,
+		}
+		rows = append(rows, rd.withTenant(row, r.TenantID))
+	}
+
+	rows = append([][]string{headers}, rows...)
+	return rows
+}
+
+func (rd *ReportData) WorkloadSLATable() [][]string {
+	headers := []string{"Workload", "Workload SLA", "Component", "Resource Type", "Redundant", "Component SLA", "Caps Workload SLA", "Resource Ids"}
+	headers = rd.withTenant(headers, "Tenant")
+	rows := [][]string{}
+	for _, w := range rd.WorkloadSLAs {
+		ids := make([]string, 0, len(w.ResourceIDs))
+		for _, id := range w.ResourceIDs {
+			ids = append(ids, MaskSubscriptionIDInResourceID(id, rd.Mask))
+		}
+
+		row := []string{
+			w.Workload,
+			w.WorkloadSLA,
+			w.Component,
+			w.ResourceType,
+			fmt.Sprintf("%t", w.Redundant),
+			w.SLA,
+			fmt.Sprintf("%t", w.Limiting),
+			strings.Join(ids, ", "),
+		}
+		rows = append(rows, rd.withTenant(row, w.TenantID))
+	}
+
+	rows = append([][]string{headers}, rows...)
+	return rows
+}
+
+// ApplySuppressions moves the findings with an active suppression to the Suppressed table.
+// Findings of expired suppressions stay active
+func (rd *ReportData) ApplySuppressions(filters *scanners.Filters, now time.Time) {
+	if filters == nil || filters.Azqr == nil || len(filters.Azqr.Suppressions) == 0 {
+		return
+	}
+
+	tags := map[string]map[string]string{}
+	for _, resources := range [][]*scanners.Resource{rd.Resources, rd.ExludedResources} {
+		for _, r := range resources {
+			tags[strings.ToLower(r.ID)] = r.Tags
+		}
+	}
+
+	aprl := []scanners.AprlResult{}
+	for _, r := range rd.Aprl {
+		s := filters.Azqr.Suppression(r.RecommendationID, r.ResourceID, tags[strings.ToLower(r.ResourceID)], now)
+		if s == nil {
+			aprl = append(aprl, r)
+			continue
+		}
+		rd.Suppressed = append(rd.Suppressed, scanners.SuppressedResult{
+			Source:           r.Source,
+			RecommendationID: r.RecommendationID,
+			Recommendation:   r.Recommendation,
+			ResourceType:     r.ResourceType,
+			ResourceID:       r.ResourceID,
+			SubscriptionID:   r.SubscriptionID,
+			SubscriptionName: r.SubscriptionName,
+			ResourceGroup:    r.ResourceGroup,
+			Name:             r.Name,
+			Justification:    s.Justification,
+			Owner:            s.Owner,
+			Approver:         s.Approver,
+			Expires:          s.Expires,
+			TenantID:         r.TenantID,
+		})
+	}
+	rd.Aprl = aprl
+
+	for _, d := range rd.Azqr {
+		resourceID := d.ResourceID()
+		for id, r := range d.Recommendations {
+			if !r.NotCompliant {
+				continue
+			}
+			s := filters.Azqr.Suppression(r.RecommendationID, resourceID, tags[resourceID], now)
+			if s == nil {
+				continue
+			}
+			source := r.Source
+			if source == "" {
+				source = "AZQR"
+			}
+			rd.Suppressed = append(rd.Suppressed, scanners.SuppressedResult{
+				Source:           source,
+				RecommendationID: r.RecommendationID,
+				Recommendation:   r.Recommendation,
+				ResourceType:     d.Type,
+				ResourceID:       resourceID,
+				SubscriptionID:   d.SubscriptionID,
+				SubscriptionName: d.SubscriptionName,
+				ResourceGroup:    d.ResourceGroup,
+				Name:             d.ServiceName,
+				Justification:    s.Justification,
+				Owner:            s.Owner,
+				Approver:         s.Approver,
+				Expires:          s.Expires,
+				TenantID:         d.TenantID,
+			})
+			delete(d.Recommendations, id)
+		}
+	}
+}
+
+// withTenant appends the tenant column to the row when the report contains several tenants
+func (rd *ReportData) withTenant(row []string, tenantID string) []string {
+	if len(rd.Tenants) == 0 {
+		return row
+	}
+	return append(row, tenantID)
+}
+
 func (rd *ReportData) ResourceIDs() []*string {
 	ids := []*string{}
 	for _, r := range rd.Resources {
@@ -270,6 +528,81 @@ func (rd *ReportData) ResourceIDs() []*string {
 	return ids
 }
 
+// SetTenant sets the tenant of all the results
+func (rd *ReportData) SetTenant(tenantID string) {
+	for i := range rd.Azqr {
+		rd.Azqr[i].TenantID = tenantID
+	}
+	for i := range rd.Aprl {
+		rd.Aprl[i].TenantID = tenantID
+	}
+	for i := range rd.Defender {
+		rd.Defender[i].TenantID = tenantID
+	}
+	for i := range rd.DefenderRecommendations {
+		rd.DefenderRecommendations[i].TenantID = tenantID
+	}
+	for i := range rd.Advisor {
+		rd.Advisor[i].TenantID = tenantID
+	}
+	for _, c := range rd.Cost.Items {
+		c.TenantID = tenantID
+	}
+	for _, r := range rd.Resources {
+		r.TenantID = tenantID
+	}
+	for _, r := range rd.ExludedResources {
+		r.TenantID = tenantID
+	}
+	for i := range rd.Errors {
+		rd.Errors[i].TenantID = tenantID
+	}
+	for i := range rd.Suppressed {
+		rd.Suppressed[i].TenantID = tenantID
+	}
+	for i := range rd.WorkloadSLAs {
+		rd.WorkloadSLAs[i].TenantID = tenantID
+	}
+	for i := range rd.ResourceTypeCount {
+		rd.ResourceTypeCount[i].TenantID = tenantID
+	}
+}
+
+// Merge appends the results of other to the report data
+func (rd *ReportData) Merge(other *ReportData) {
+	for t, recommendations := range other.Recommendations {
+		if rd.Recommendations[t] == nil {
+			rd.Recommendations[t] = map[string]scanners.AprlRecommendation{}
+		}
+		for id, r := range recommendations {
+			rd.Recommendations[t][id] = r
+		}
+	}
+
+	// the scan of the tenants started with the first one
+	if !other.ScannedAt.IsZero() && (rd.ScannedAt.IsZero() || other.ScannedAt.Before(rd.ScannedAt)) {
+		rd.ScannedAt = other.ScannedAt
+	}
+
+	rd.Azqr = append(rd.Azqr, other.Azqr...)
+	rd.Aprl = append(rd.Aprl, other.Aprl...)
+	rd.Defender = append(rd.Defender, other.Defender...)
+	rd.DefenderRecommendations = append(rd.DefenderRecommendations, other.DefenderRecommendations...)
+	rd.Advisor = append(rd.Advisor, other.Advisor...)
+	rd.Resources = append(rd.Resources, other.Resources...)
+	rd.ExludedResources = append(rd.ExludedResources, other.ExludedResources...)
+	rd.ResourceTypeCount = append(rd.ResourceTypeCount, other.ResourceTypeCount...)
+	rd.Errors = append(rd.Errors, other.Errors...)
+	rd.Suppressed = append(rd.Suppressed, other.Suppressed...)
+	rd.WorkloadSLAs = append(rd.WorkloadSLAs, other.WorkloadSLAs...)
+
+	if other.Cost != nil && len(other.Cost.Items) > 0 {
+		rd.Cost.From = other.Cost.From
+		rd.Cost.To = other.Cost.To
+		rd.Cost.Items = append(rd.Cost.Items, other.Cost.Items...)
+	}
+}
+
 func NewReportData(outputFile string, mask bool) ReportData {
 	return ReportData{
 		OutputFileName:          outputFile,
@@ -284,6 +617,9 @@ func NewReportData(outputFile string, mask bool) ReportData {
 			Items: []*scanners.CostResultItem{},
 		},
 		ResourceTypeCount: []scanners.ResourceTypeCount{},
+		Errors:            []scanners.ScanError{},
+		Suppressed:        []scanners.SuppressedResult{},
+		WorkloadSLAs:      []scanners.WorkloadSLA{},
 	}
 }
 
@@ -317,6 +653,7 @@ func MaskSubscriptionIDInResourceID(resourceID string, mask bool) string {
 
 func (rd *ReportData) resourcesTable(resources []*scanners.Resource) [][]string {
 	headers := []string{"Subscription Id", "Resource Group", "Location", "Resource Type", "Resource Name", "Sku Name", "Sku Tier", "Kind", "SLA", "Resource Id"}
+	headers = rd.withTenant(headers, "Tenant")
 
 	rows := [][]string{}
 	for _, r := range resources {
@@ -348,7 +685,7 @@ func (rd *ReportData) resourcesTable(resources []*scanners.Resource) [][]string
 			sla,
 			MaskSubscriptionIDInResourceID(r.ID, rd.Mask),
 		}
-		rows = append(rows, row)
+		rows = append(rows, rd.withTenant(row, r.TenantID))
 	}
 
 	rows = append([][]string{headers}, rows...)
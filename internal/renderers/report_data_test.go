@@ -0,0 +1,381 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+func TestReportData_MergeTenants(t *testing.T) {
+	rd := NewReportData("report", false)
+
+	for _, tenant := range []string{"tenant-a", "tenant-b"} {
+		data := NewReportData("report", false)
+		data.Defender = []scanners.DefenderResult{{SubscriptionID: "00000000-0000-0000-0000-000000000000", Name: "VirtualMachines", Tier: "Standard"}}
+		data.Resources = []*scanners.Resource{{ID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/a/b/c"}}
+		data.SetTenant(tenant)
+		rd.Merge(&data)
+	}
+
+	table := rd.DefenderTable()
+	if len(table[0]) != 4 {
+		t.Errorf("expected no Tenant column in a single tenant report, got %v", table[0])
+	}
+
+	rd.Tenants = []string{"tenant-a", "tenant-b"}
+	for _, table := range [][][]string{rd.DefenderTable(), rd.ResourcesTable()} {
+		if len(table) != 3 {
+			t.Fatalf("expected 2 rows, got %d", len(table)-1)
+		}
+		last := len(table[0]) - 1
+		if table[0][last] != "Tenant" || table[1][last] != "tenant-a" || table[2][last] != "tenant-b" {
+			t.Errorf("unexpected Tenant column: %v", table)
+		}
+	}
+}
+
+func TestReportData_RecommendationsTableTenants(t *testing.T) {
+	rd := NewReportData("report", false)
+	r := scanners.AzqrRecommendation{RecommendationID: "st-001", ResourceType: "Microsoft.Storage/storageAccounts"}
+	rd.Recommendations["microsoft.storage/storageaccounts"] = map[string]scanners.AprlRecommendation{"st-001": r.ToAzureAprlRecommendation()}
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		TenantID:        "tenant-a",
+		Recommendations: map[string]scanners.AzqrResult{"st-001": {RecommendationID: "st-001", NotCompliant: true}},
+	}}
+	rd.ResourceTypeCount = []scanners.ResourceTypeCount{{ResourceType: "Microsoft.Storage/storageAccounts", Count: 1, TenantID: "tenant-a"}}
+	rd.Tenants = []string{"tenant-a", "tenant-b"}
+
+	table := rd.RecommendationsTable()
+	if len(table) != 3 {
+		t.Fatalf("expected a row per tenant, got %d", len(table)-1)
+	}
+	for i, want := range [][]string{{"false", "1", "tenant-a"}, {"true", "0", "tenant-b"}} {
+		row := table[i+1]
+		if got := []string{row[0], row[1], row[len(row)-1]}; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+
+	table = rd.ResourceTypesTable()
+	if last := len(table[0]) - 1; table[0][last] != "Tenant" || table[1][last] != "tenant-a" {
+		t.Errorf("unexpected Tenant column: %v", table)
+	}
+}
+
+func TestReportData_ImpactedTableSource(t *testing.T) {
+	rd := NewReportData("report", false)
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		SubscriptionID: "00000000-0000-0000-0000-000000000000",
+		Type:           "Microsoft.Storage/storageAccounts",
+		ServiceName:    "st",
+		Recommendations: map[string]scanners.AzqrResult{
+			"st-001":      {RecommendationID: "st-001", NotCompliant: true},
+			"contoso-001": {RecommendationID: "contoso-001", NotCompliant: true, Source: scanners.SourcePolicy},
+		},
+	}}
+
+	sources := map[string]string{}
+	for _, row := range rd.ImpactedTable()[1:] {
+		sources[row[6]] = row[1]
+	}
+	if sources["st-001"] != "AZQR" || sources["contoso-001"] != scanners.SourcePolicy {
+		t.Errorf("unexpected sources %v", sources)
+	}
+}
+
+func TestReportData_RecommendationsTableImplemented(t *testing.T) {
+	rd := NewReportData("report", false)
+	rd.Recommendations["Microsoft.Storage/storageAccounts"] = map[string]scanners.AprlRecommendation{}
+	for _, id := range []string{"st-001", "st-002", "st-003"} {
+		r := scanners.AzqrRecommendation{RecommendationID: id, ResourceType: "Microsoft.Storage/storageAccounts"}
+		rd.Recommendations["Microsoft.Storage/storageAccounts"][id] = r.ToAzureAprlRecommendation()
+	}
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		Recommendations: map[string]scanners.AzqrResult{
+			"st-001": {RecommendationID: "st-001", Status: scanners.StatusCompliant},
+			"st-002": {RecommendationID: "st-002", Status: scanners.StatusNotCompliant, NotCompliant: true},
+			"st-003": {RecommendationID: "st-003", Status: scanners.StatusError},
+		},
+	}}
+
+	implemented := map[string]string{}
+	for _, row := range rd.RecommendationsTable()[1:] {
+		implemented[row[11]] = row[0]
+	}
+	if implemented["st-001"] != "true" || implemented["st-002"] != "false" || implemented["st-003"] != "unknown" {
+		t.Errorf("unexpected Implemented column %v", implemented)
+	}
+}
+
+func TestReportData_ImpactedTableStatus(t *testing.T) {
+	rd := NewReportData("report", false)
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		SubscriptionID: "0000",
+		ResourceGroup:  "rg",
+		Type:           "Microsoft.Storage/storageAccounts",
+		ServiceName:    "st",
+		Recommendations: map[string]scanners.AzqrResult{
+			"st-001": {RecommendationID: "st-001", Status: scanners.StatusCompliant},
+			"st-002": {RecommendationID: "st-002", Status: scanners.StatusNotCompliant, NotCompliant: true},
+			"st-003": {RecommendationID: "st-003", Status: scanners.StatusError},
+			"st-004": {RecommendationID: "st-004", Status: scanners.StatusNotApplicable},
+		},
+	}}
+
+	table := rd.ImpactedTable()
+	column := len(table[0]) - 1
+	statuses := map[string]string{}
+	for _, row := range table[1:] {
+		statuses[row[6]] = row[column]
+	}
+	expected := map[string]string{"st-002": "NotCompliant"}
+	if table[0][column] != "Status" || !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("expected only the not compliant resource in the impacted table, got %v", statuses)
+	}
+	if errors := rd.ErrorsTable(); len(errors) != 2 || errors[1][0] != scanners.PhaseRule || errors[1][3] != "st-003" {
+		t.Errorf("expected the rule error in the errors table, got %v", errors)
+	}
+	if findings := rd.Findings(); len(findings) != 1 || findings[0].RecommendationID != "st-002" {
+		t.Errorf("expected only the not compliant resource in the findings, got %v", findings)
+	}
+}
+
+func TestReportData_ImpactedTableResourceGroupID(t *testing.T) {
+	rd := NewReportData("report", false)
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		SubscriptionID: "0000",
+		ResourceGroup:  "rg",
+		Type:           "Microsoft.Resources/resourceGroups",
+		ServiceName:    "rg",
+		ID:             "/subscriptions/0000/resourceGroups/rg",
+		Recommendations: map[string]scanners.AzqrResult{
+			"rg-007": {RecommendationID: "rg-007", Status: scanners.StatusNotCompliant, NotCompliant: true},
+		},
+	}}
+
+	table := rd.ImpactedTable()
+	if len(table) != 2 || table[1][11] != "/subscriptions/0000/resourcegroups/rg" {
+		t.Errorf("expected the resource id of the resource group, got %v", table[1:])
+	}
+}
+
+func TestReportData_Tables(t *testing.T) {
+	rd := NewReportData("report", false)
+	names := map[string]bool{}
+	for _, table := range rd.Tables() {
+		if names[table.Name] || table.Title == "" || len(table.Records) == 0 {
+			t.Errorf("invalid table %s", table.Name)
+		}
+		names[table.Name] = true
+	}
+	for _, name := range []string{"resourceType", "defenderRecommendations", "errors", "suppressed", "workloads"} {
+		if !names[name] {
+			t.Errorf("missing table %s", name)
+		}
+	}
+}
+
+func TestReportData_ApplySuppressions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "filters.yaml")
+	content := `
+azqr:
+  suppressions:
+    - {recommendationId: st-001, tags: {environment: dev}, justification: Development only, owner: contoso, expires: 2026-06-30}
+    - {recommendationId: aprl-001, resources: [/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st], justification: Accepted, expires: 2026-06-30}
+`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	filters, err := scanners.LoadFilters(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"
+	newData := func() ReportData {
+		rd := NewReportData("report", false)
+		rd.Resources = []*scanners.Resource{{ID: id, Tags: map[string]string{"environment": "dev"}}}
+		rd.Aprl = []scanners.AprlResult{{RecommendationID: "aprl-001", ResourceID: id}}
+		rd.Azqr = []scanners.AzqrServiceResult{{
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			ResourceGroup:  "rg",
+			Type:           "Microsoft.Storage/storageAccounts",
+			ServiceName:    "st",
+			Recommendations: map[string]scanners.AzqrResult{
+				"st-001": {RecommendationID: "st-001", NotCompliant: true},
+				"st-002": {RecommendationID: "st-002", NotCompliant: true},
+			},
+		}}
+		return rd
+	}
+
+	rd := newData()
+	rd.ApplySuppressions(filters, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if len(rd.Suppressed) != 2 || len(rd.Aprl) != 0 || len(rd.ImpactedTable()) != 2 {
+		t.Errorf("expected 2 suppressed findings and 1 impacted resource, got %v", rd.SuppressedTable())
+	}
+
+	rd = newData()
+	rd.ApplySuppressions(filters, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC))
+	if len(rd.Suppressed) != 0 || len(rd.ImpactedTable()) != 4 {
+		t.Errorf("expected the expired suppressions to be ignored, got %v", rd.SuppressedTable())
+	}
+}
+
+func TestReportData_Remediation(t *testing.T) {
+	subscriptionID := "00000000-0000-0000-0000-000000000001"
+	remediation := &scanners.Remediation{
+		AzCli:     "az storage account update --ids {{.ResourceID}} --https-only true",
+		Terraform: "https_traffic_only_enabled = true",
+	}
+
+	rd := NewReportData("report", true)
+	rd.Remediation = true
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		SubscriptionID:   subscriptionID,
+		SubscriptionName: "sub",
+		ResourceGroup:    "rg",
+		Type:             "Microsoft.Storage/storageAccounts",
+		ServiceName:      "st",
+		Recommendations: map[string]scanners.AzqrResult{
+			"st-007": {RecommendationID: "st-007", NotCompliant: true, Remediation: remediation},
+			"st-009": {RecommendationID: "st-009", NotCompliant: true},
+			"st-011": {RecommendationID: "st-011", Remediation: remediation},
+		},
+	}}
+
+	id := "/subscriptions/" + subscriptionID + "/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"
+	masked := MaskSubscriptionIDInResourceID(id, true)
+
+	table := rd.ImpactedTable()
+	last := len(table[0]) - 1
+	if table[0][last] != "Remediation" {
+		t.Fatalf("expected a Remediation column, got %v", table[0])
+	}
+	fixes := map[string]string{}
+	for _, row := range table[1:] {
+		fixes[row[6]] = row[last]
+	}
+	expected := "az storage account update --ids " + masked + " --https-only true\nTerraform: https_traffic_only_enabled = true"
+	if len(fixes) != 2 || fixes["st-007"] != expected || fixes["st-009"] != "" {
+		t.Errorf("unexpected remediations %v", fixes)
+	}
+
+	scripts := rd.RemediationScripts()
+	if len(scripts) != 1 || scripts[0].SubscriptionID != subscriptionID {
+		t.Fatalf("expected a script for the subscription, got %v", scripts)
+	}
+	content := scripts[0].Content
+	for _, line := range []string{
+		"az account set --subscription " + subscriptionID,
+		"# st-007: ",
+		"az storage account update --ids " + id + " --https-only true",
+		"# Terraform: https_traffic_only_enabled = true",
+	} {
+		if !strings.Contains(content, line) {
+			t.Errorf("expected %q in the script:\n%s", line, content)
+		}
+	}
+	if strings.Contains(content, "st-009") || strings.Contains(content, "st-011") {
+		t.Errorf("unexpected findings without remediation in the script:\n%s", content)
+	}
+
+	rd.Remediation = false
+	if table := rd.ImpactedTable(); table[0][len(table[0])-1] != "Status" {
+		t.Errorf("expected no Remediation column, got %v", table[0])
+	}
+}
+
+func TestReportData_NewAndResolvedFindings(t *testing.T) {
+	aprl := func(id, resourceID string) scanners.AprlResult {
+		return scanners.AprlResult{RecommendationID: id, ResourceID: resourceID}
+	}
+
+	rd := NewReportData("report", false)
+	rd.Aprl = []scanners.AprlResult{aprl("aprl-1", "/subscriptions/0000/resourceGroups/rg/providers/a/b/c"), aprl("aprl-2", "/subscriptions/0000/resourceGroups/rg/providers/a/b/c")}
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		SubscriptionID: "0000",
+		ResourceGroup:  "rg",
+		Type:           "a/b",
+		ServiceName:    "c",
+		Recommendations: map[string]scanners.AzqrResult{
+			"azqr-1": {RecommendationID: "azqr-1", NotCompliant: true},
+			"azqr-2": {RecommendationID: "azqr-2"},
+		},
+	}}
+
+	if len(rd.NewFindings()) != 3 || len(rd.ResolvedFindings()) != 0 {
+		t.Errorf("expected all findings to be new without a baseline")
+	}
+
+	rd.Baseline = NewFindings([]scanners.AprlResult{
+		aprl("aprl-1", "/SUBSCRIPTIONS/0000/resourceGroups/rg/providers/a/b/c"),
+		aprl("aprl-3", "/subscriptions/0000/resourceGroups/rg/providers/a/b/c"),
+	}, nil)
+
+	keys := func(findings []Finding) []string {
+		result := []string{}
+		for _, f := range findings {
+			result = append(result, f.RecommendationID)
+		}
+		return result
+	}
+	if n := keys(rd.NewFindings()); len(n) != 2 || n[0] != "aprl-2" || n[1] != "azqr-1" {
+		t.Errorf("unexpected new findings %v", n)
+	}
+	if r := keys(rd.ResolvedFindings()); len(r) != 1 || r[0] != "aprl-3" {
+		t.Errorf("unexpected resolved findings %v", r)
+	}
+}
+
+func TestSaveFindings(t *testing.T) {
+	rd := NewReportData("report", true)
+	rd.Aprl = []scanners.AprlResult{{RecommendationID: "aprl-1", ResourceID: "/subscriptions/0000/resourceGroups/rg/providers/a/b/c"}}
+	rd.Azqr = []scanners.AzqrServiceResult{{
+		SubscriptionID: "0000",
+		ResourceGroup:  "rg",
+		Type:           "a/b",
+		ServiceName:    "c",
+		Recommendations: map[string]scanners.AzqrResult{
+			"azqr-1": {RecommendationID: "azqr-1", NotCompliant: true},
+		},
+	}}
+
+	file := filepath.Join(t.TempDir(), "report.findings.json")
+	scannedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if err := SaveFindings(file, scannedAt, rd.Findings()); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := LoadFindingsFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.ScannedAt.Equal(scannedAt) {
+		t.Errorf("expected the scan time %s, got %s", scannedAt, saved.ScannedAt)
+	}
+	baseline, err := LoadFindings(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rd.Baseline = baseline
+	if !reflect.DeepEqual(baseline, rd.Findings()) || len(rd.NewFindings()) != 0 || len(rd.ResolvedFindings()) != 0 {
+		t.Errorf("expected the saved findings to be the baseline of the same scan, got %v", baseline)
+	}
+
+	// the findings files of older versions only have the findings
+	if err := os.WriteFile(file, []byte(`[{"RecommendationID": "aprl-1"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if baseline, err := LoadFindings(file); err != nil || len(baseline) != 1 || baseline[0].RecommendationID != "aprl-1" {
+		t.Errorf("expected the findings of an older version, got %v: %v", baseline, err)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+const (
+	schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	version = "2.1.0"
+
+	// fingerprintKey identifies the fingerprint of a finding: its recommendation and resource
+	fingerprintKey = "azqrFinding/v1"
+)
+
+type (
+	sarifLog struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []run  `json:"runs"`
+	}
+
+	run struct {
+		Tool    tool     `json:"tool"`
+		Results []result `json:"results"`
+	}
+
+	tool struct {
+		Driver driver `json:"driver"`
+	}
+
+	driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+
+	rule struct {
+		ID                   string         `json:"id"`
+		Name                 string         `json:"name,omitempty"`
+		ShortDescription     message        `json:"shortDescription"`
+		FullDescription      message        `json:"fullDescription"`
+		Help                 help           `json:"help"`
+		HelpURI              string         `json:"helpUri,omitempty"`
+		DefaultConfiguration configuration  `json:"defaultConfiguration"`
+		Properties           ruleProperties `json:"properties"`
+	}
+
+	ruleProperties struct {
+		Tags []string `json:"tags,omitempty"`
+		// SecuritySeverity is the severity used by GitHub code scanning
+		SecuritySeverity string `json:"security-severity"`
+	}
+
+	help struct {
+		Text     string `json:"text"`
+		Markdown string `json:"markdown"`
+	}
+
+	message struct {
+		Text string `json:"text"`
+	}
+
+	configuration struct {
+		Level string `json:"level"`
+	}
+
+	result struct {
+		RuleID              string            `json:"ruleId"`
+		RuleIndex           int               `json:"ruleIndex"`
+		Level               string            `json:"level"`
+		Message             message           `json:"message"`
+		Locations           []location        `json:"locations"`
+		PartialFingerprints map[string]string `json:"partialFingerprints"`
+	}
+
+	location struct {
+		PhysicalLocation physicalLocation  `json:"physicalLocation"`
+		LogicalLocations []logicalLocation `json:"logicalLocations"`
+	}
+
+	// physicalLocation - Code scanning dashboards require a physical location, so the resource id is used as the artifact
+	physicalLocation struct {
+		ArtifactLocation artifactLocation `json:"artifactLocation"`
+		Region           region           `json:"region"`
+	}
+
+	artifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	region struct {
+		StartLine int `json:"startLine"`
+	}
+
+	logicalLocation struct {
+		Name               string `json:"name"`
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Kind               string `json:"kind"`
+	}
+)
+
+// CreateSarifReport renders the recommendations as SARIF rules and the impacted resources as SARIF results
+func CreateSarifReport(data *renderers.ReportData) error {
+	filename := fmt.Sprintf("%s.sarif", data.OutputFileName)
+	log.Info().Msgf("Generating Report: %s", filename)
+
+	js, err := json.MarshalIndent(newSarifLog(data), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling sarif: %w", err)
+	}
+
+	if err := os.WriteFile(filename, js, 0644); err != nil {
+		return fmt.Errorf("error writing sarif: %w", err)
+	}
+	return nil
+}
+
+func newSarifLog(data *renderers.ReportData) sarifLog {
+	recommendations := map[string]scanners.AprlRecommendation{}
+	for _, rt := range data.Recommendations {
+		for id, r := range rt {
+			recommendations[id] = r
+		}
+	}
+
+	ids := make([]string, 0, len(recommendations))
+	for id := range recommendations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := []rule{}
+	index := map[string]int{}
+	for _, id := range ids {
+		index[id] = len(rules)
+		rules = append(rules, newRule(recommendations[id]))
+	}
+
+	results := []result{}
+	for _, f := range data.Findings() {
+		if _, ok := index[f.RecommendationID]; !ok {
+			// findings without a recommendation in the report, e.g. custom rules loaded after the scan
+			index[f.RecommendationID] = len(rules)
+			rules = append(rules, newRule(scanners.AprlRecommendation{
+				RecommendationID: f.RecommendationID,
+				Recommendation:   f.Recommendation,
+				Category:         f.Category,
+				Impact:           f.Impact,
+				ResourceType:     f.ResourceType,
+				Source:           f.Source,
+			}))
+		}
+
+		// only the displayed resource id is masked, the fingerprint is stable across masked and unmasked scans
+		resourceID := renderers.MaskSubscriptionIDInResourceID(f.ResourceID, data.Mask)
+		text := fmt.Sprintf("%s: %s", f.ResourceName, f.Recommendation)
+		if f.Result != "" {
+			text = fmt.Sprintf("%s (%s)", text, f.Result)
+		}
+
+		results = append(results, result{
+			RuleID:    f.RecommendationID,
+			RuleIndex: index[f.RecommendationID],
+			Level:     level(f.Impact),
+			Message:   message{Text: text},
+			Locations: []location{{
+				PhysicalLocation: physicalLocation{
+					ArtifactLocation: artifactLocation{URI: strings.TrimPrefix(resourceID, "/")},
+					Region:           region{StartLine: 1},
+				},
+				LogicalLocations: []logicalLocation{{
+					Name:               f.ResourceName,
+					FullyQualifiedName: resourceID,
+					Kind:               "resource",
+				}},
+			}},
+			PartialFingerprints: map[string]string{
+				fingerprintKey: fingerprint(f),
+			},
+		})
+	}
+
+	return sarifLog{
+		Schema:  schema,
+		Version: version,
+		Runs: []run{{
+			Tool: tool{Driver: driver{
+				Name:           "azqr",
+				InformationURI: "https://azure.github.io/azqr",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func newRule(r scanners.AprlRecommendation) rule {
+	learn := ""
+	if len(r.LearnMoreLink) > 0 {
+		learn = r.LearnMoreLink[0].Url
+	}
+
+	description := r.LongDescription
+	if description == "" {
+		description = r.Recommendation
+	}
+
+	text := description
+	markdown := description
+	if r.PotentialBenefits != "" {
+		text = fmt.Sprintf("%s\n\nPotential benefits: %s", text, r.PotentialBenefits)
+		markdown = fmt.Sprintf("%s\n\n**Potential benefits:** %s", markdown, r.PotentialBenefits)
+	}
+	if learn != "" {
+		text = fmt.Sprintf("%s\n\nLearn more: %s", text, learn)
+		markdown = fmt.Sprintf("%s\n\n[Learn more](%s)", markdown, learn)
+	}
+
+	tags := []string{}
+	for _, t := range []string{r.Category, r.Source, r.ResourceType} {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	return rule{
+		ID:                   r.RecommendationID,
+		Name:                 r.RecommendationID,
+		ShortDescription:     message{Text: r.Recommendation},
+		FullDescription:      message{Text: description},
+		Help:                 help{Text: text, Markdown: markdown},
+		HelpURI:              learn,
+		DefaultConfiguration: configuration{Level: level(r.Impact)},
+		Properties: ruleProperties{
+			Tags:             tags,
+			SecuritySeverity: securitySeverity(r.Impact),
+		},
+	}
+}
+
+// level maps the impact of a recommendation to a SARIF level
+func level(impact string) string {
+	switch scanners.RecommendationImpact(impact) {
+	case scanners.ImpactHigh:
+		return "error"
+	case scanners.ImpactMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// securitySeverity maps the impact of a recommendation to the GitHub security severity (high, medium, low)
+func securitySeverity(impact string) string {
+	switch scanners.RecommendationImpact(impact) {
+	case scanners.ImpactHigh:
+		return "8.0"
+	case scanners.ImpactMedium:
+		return "5.0"
+	default:
+		return "2.0"
+	}
+}
+
+// fingerprint identifies a finding across runs with the hash of its key
+func fingerprint(f renderers.Finding) string {
+	sum := sha256.Sum256([]byte(f.Key()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package sarif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+func TestNewSarifLog_Fingerprint(t *testing.T) {
+	// the fingerprint of a finding must not change across releases, or the alerts of the dashboards are reopened
+	expected := "fc1ff6a0d19844a10db3be2a39a48caba934ff5e8a115bf8a443a8dc8e8fe2cc"
+
+	for _, mask := range []bool{false, true} {
+		rd := renderers.NewReportData("report", mask)
+		rd.Azqr = []scanners.AzqrServiceResult{{
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			ResourceGroup:  "rg",
+			Type:           "Microsoft.Storage/storageAccounts",
+			ServiceName:    "st",
+			Recommendations: map[string]scanners.AzqrResult{
+				"st-001": {RecommendationID: "st-001", Impact: scanners.ImpactHigh, Status: scanners.StatusNotCompliant, NotCompliant: true},
+				"st-002": {RecommendationID: "st-002", Status: scanners.StatusNotApplicable},
+			},
+		}}
+
+		results := newSarifLog(&rd).Runs[0].Results
+		if len(results) != 1 {
+			t.Fatalf("mask %t: expected only the not compliant result, got %d", mask, len(results))
+		}
+		if fingerprint := results[0].PartialFingerprints[fingerprintKey]; fingerprint != expected {
+			t.Errorf("mask %t: expected fingerprint %s, got %s", mask, expected, fingerprint)
+		}
+
+		uri := results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI
+		if strings.Contains(uri, "00000000-0000-0000-0000-000000000000") == mask {
+			t.Errorf("mask %t: unexpected uri %s", mask, uri)
+		}
+	}
+}
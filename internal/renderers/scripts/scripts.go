@@ -0,0 +1,33 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scripts
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+// CreateRemediationScripts writes the remediation script of each subscription with fixes
+func CreateRemediationScripts(data *renderers.ReportData) error {
+	scripts := data.RemediationScripts()
+	if len(scripts) == 0 {
+		log.Info().Msg("Skipping remediation scripts. No findings with a remediation")
+		return nil
+	}
+
+	for _, s := range scripts {
+		filename := fmt.Sprintf("%s.%s.remediation.sh", data.OutputFileName, s.SubscriptionID)
+		log.Info().Msgf("Generating Remediation Script: %s", filename)
+
+		// the script is executable, so engineers can run it after the review
+		if err := os.WriteFile(filename, []byte(s.Content), 0700); err != nil {
+			return fmt.Errorf("error writing remediation script: %w", err)
+		}
+	}
+	return nil
+}
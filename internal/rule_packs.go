@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/rs/zerolog/log"
+)
+
+// sources of the built-in recommendations, which cannot be used by a rule pack
+var reservedSources = []string{"APRL", "AOR", "AZQR"}
+
+// RulePack - Custom Resource Graph recommendations loaded from a directory.
+// The directory uses the APRL layout: YAML files with the recommendations and
+// *.kql files, named after the recommendation ids, with their graph queries.
+type RulePack struct {
+	// Source is reported in the Source column of the recommendations (e.g. CONTOSO)
+	Source string
+	// Dir is the directory of the rule pack
+	Dir string
+
+	recommendations map[string]map[string]scanners.AprlRecommendation
+}
+
+// LoadRulePacks loads the rule packs of the given directories.
+// Each directory is given as <dir> or <source>=<dir>. Without a source, the upper case name of the directory is used
+func LoadRulePacks(dirs []string) ([]RulePack, error) {
+	packs := []RulePack{}
+	for _, d := range dirs {
+		pack, err := LoadRulePack(d)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
+
+// LoadRulePack loads the rule pack of a directory, given as <dir> or <source>=<dir>
+func LoadRulePack(dir string) (RulePack, error) {
+	source := ""
+	if i := strings.Index(dir, "="); i > 0 {
+		source, dir = dir[:i], dir[i+1:]
+	}
+	if source == "" {
+		source = filepath.Base(filepath.Clean(dir))
+	}
+	source = strings.ToUpper(source)
+
+	for _, s := range reservedSources {
+		if source == s {
+			return RulePack{}, fmt.Errorf("rule pack %s cannot use the reserved source %s", dir, source)
+		}
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return RulePack{}, fmt.Errorf("failed to read rule pack: %w", err)
+	}
+	if !info.IsDir() {
+		return RulePack{}, fmt.Errorf("rule pack %s is not a directory", dir)
+	}
+
+	recommendations, err := loadAprlRecommendations(os.DirFS(dir))
+	if err != nil {
+		return RulePack{}, fmt.Errorf("failed to load rule pack %s: %w", dir, err)
+	}
+
+	count := 0
+	for t, rs := range recommendations {
+		for id, r := range rs {
+			if r.RecommendationID == "" || r.ResourceType == "" {
+				return RulePack{}, fmt.Errorf("rule pack %s has a recommendation without aprlGuid or recommendationResourceType", dir)
+			}
+			if r.GraphQuery == "" {
+				log.Warn().Msgf("Recommendation %s of rule pack %s has no %s.kql query. It will not be validated", id, source, id)
+			}
+
+			// the Learn column is read from the first link
+			if len(r.LearnMoreLink) == 0 {
+				r.LearnMoreLink = append(r.LearnMoreLink, struct {
+					Name string `yaml:"name"`
+					Url  string `yaml:"url"`
+				}{Name: "Learn More"})
+			}
+			r.Source = source
+			recommendations[t][id] = r
+			count++
+		}
+	}
+
+	log.Info().Msgf("Loaded %d recommendations from rule pack %s (%s)", count, source, dir)
+	return RulePack{Source: source, Dir: dir, recommendations: recommendations}, nil
+}
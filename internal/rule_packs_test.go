@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+func writeRulePack(t *testing.T, name string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(filepath.Join(dir, "storage", "kql"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	recommendations := `
+- description: Storage Accounts should have a cost center tag
+  aprlGuid: contoso-st-001
+  recommendationTypeId: null
+  recommendationControl: Governance
+  recommendationImpact: Low
+  recommendationResourceType: Microsoft.Storage/storageAccounts
+  recommendationMetadataState: Active
+  longDescription: Storage Accounts should have a cost center tag
+  potentialBenefits: Cost allocation
+  pgVerified: false
+  automationAvailable: true
+`
+	query := "resources | where type =~ 'microsoft.storage/storageaccounts' and isnull(tags['costCenter']) | project recommendationId = 'contoso-st-001', name, id, tags"
+
+	if err := os.WriteFile(filepath.Join(dir, "storage", "recommendations.yaml"), []byte(recommendations), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "storage", "kql", "contoso-st-001.kql"), []byte(query), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadRulePack(t *testing.T) {
+	dir := writeRulePack(t, "contoso")
+
+	pack, err := LoadRulePack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Source != "CONTOSO" {
+		t.Errorf("expected the directory name as source, got %s", pack.Source)
+	}
+
+	r, ok := pack.recommendations["microsoft.storage/storageaccounts"]["contoso-st-001"]
+	if !ok {
+		t.Fatal("expected recommendation contoso-st-001")
+	}
+	if !strings.HasPrefix(r.GraphQuery, "resources") {
+		t.Errorf("expected the kql query, got %q", r.GraphQuery)
+	}
+	if r.Source != "CONTOSO" || len(r.LearnMoreLink) != 1 {
+		t.Errorf("unexpected recommendation %+v", r)
+	}
+
+	pack, err = LoadRulePack("internal-checks=" + dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Source != "INTERNAL-CHECKS" {
+		t.Errorf("expected the given source, got %s", pack.Source)
+	}
+}
+
+func TestLoadRulePack_Invalid(t *testing.T) {
+	dir := writeRulePack(t, "packs")
+
+	if _, err := LoadRulePack("aprl=" + dir); err == nil {
+		t.Error("expected an error for a reserved source")
+	}
+	if _, err := LoadRulePack(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("not: [a list"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRulePack(dir); err == nil {
+		t.Error("expected an error for an invalid recommendations file")
+	}
+}
+
+func TestAprlScanner_RulePacks(t *testing.T) {
+	packs, err := LoadRulePacks([]string{writeRulePack(t, "contoso")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aprlScanner := NewAprlScanner(nil, nil, nil)
+	aprlScanner.AddRulePacks(packs...)
+
+	r, ok := aprlScanner.GetAprlRecommendations()["microsoft.storage/storageaccounts"]["contoso-st-001"]
+	if !ok || r.Source != "CONTOSO" {
+		t.Errorf("expected the rule pack recommendation with its source, got %+v", r)
+	}
+}
+
+func TestAprlScanner_RulePackTypes(t *testing.T) {
+	aprlScanner := NewAprlScanner(nil, nil, nil)
+	aprlScanner.AddRulePacks(RulePack{
+		Source: "CONTOSO",
+		recommendations: map[string]map[string]scanners.AprlRecommendation{
+			"microsoft.contoso/widgets": {"contoso-wd-001": {RecommendationID: "contoso-wd-001"}},
+		},
+	})
+
+	types := aprlScanner.rulePackTypes()
+	if len(types) != 1 || types[0] != "microsoft.contoso/widgets" {
+		t.Errorf("expected the resource type without a service scanner, got %v", types)
+	}
+
+	filters, err := scanners.LoadFilters("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filters.Azqr.AddSubscription("0000")
+	aprlScanner.filters = filters
+
+	results := aprlScanner.filterResults([]scanners.AprlResult{
+		{RecommendationID: "contoso-wd-001", ResourceType: "Microsoft.Contoso/widgets", ResourceID: "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Contoso/widgets/w1"},
+		{RecommendationID: "contoso-wd-001", ResourceType: "Microsoft.Contoso/widgets", ResourceID: "/subscriptions/1111/resourceGroups/rg/providers/Microsoft.Contoso/widgets/w2"},
+		{RecommendationID: "contoso-gd-001", ResourceType: "Microsoft.Contoso/gadgets", ResourceID: "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Contoso/gadgets/g1"},
+	}, map[string]bool{"microsoft.contoso/widgets": true})
+	if len(results) != 1 || !strings.HasSuffix(results[0].ResourceID, "/w1") {
+		t.Errorf("expected only the rule pack result in scope, got %v", results)
+	}
+}
@@ -0,0 +1,923 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rules
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression - Compiled rule expression.
+//
+// The language borrows the syntax of CEL but is not CEL: values are JSON values and there are no types to declare.
+//   - literals: strings ('a' or "a"), numbers (42, 1.5), true, false, null and lists ([1, 2])
+//   - member access (resource.properties.name) and indexes (resource.tags['env'], list[0]).
+//     Missing members evaluate to null, like JSONPath
+//   - operators: ! - * / % + - == != < <= > >= in && || and the ternary ?:
+//   - functions: has(path), true when the member is not null, size(x), string(x), int(x) and the methods
+//     startsWith, endsWith, contains, matches, lowerAscii, upperAscii and size
+//   - macros on lists: list.exists(x, predicate), list.all(x, predicate) and list.filter(x, predicate)
+//
+// All numbers are float64, as in JSON: int(x) truncates a number or parses a string, and returns a float64
+type Expression struct {
+	source string
+	root   node
+}
+
+// Compile parses an expression
+func Compile(source string) (*Expression, error) {
+	p := &parser{lexer: lexer{input: source}}
+	p.next()
+	root, err := p.parseExpression()
+	if err == nil {
+		err = p.err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	if p.token.kind != tokenEOF {
+		return nil, fmt.Errorf("invalid expression %q: unexpected %s at position %d", source, p.token.text, p.token.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression with the given variables.
+// Variables hold JSON values: nil, bool, float64, string, []interface{} and map[string]interface{}
+func (e *Expression) Eval(vars map[string]interface{}) (interface{}, error) {
+	return e.root.eval(&env{vars: vars})
+}
+
+// EvalBool evaluates an expression that must return a bool
+func (e *Expression) EvalBool(vars map[string]interface{}) (bool, error) {
+	v, err := e.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returned %s, expected a bool", e.source, typeName(v))
+	}
+	return b, nil
+}
+
+// env - Variables of an evaluation. Macros add a scope for their iteration variable
+type env struct {
+	vars   map[string]interface{}
+	parent *env
+}
+
+func (e *env) lookup(name string) (interface{}, bool) {
+	for s := e; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+var punctuation = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "%", ".", ",", "(", ")", "[", "]", "?", ":"}
+
+// digits skips the digits at the current position
+func (l *lexer) digits() {
+	for l.pos < len(l.input) && unicode.IsDigit(rune(l.input[l.pos])) {
+		l.pos++
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, text: "end of expression", pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.input[l.pos]
+	switch {
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for l.pos < len(l.input) && (l.input[l.pos] == '_' || unicode.IsLetter(rune(l.input[l.pos])) || unicode.IsDigit(rune(l.input[l.pos]))) {
+			l.pos++
+		}
+		return token{kind: tokenIdent, text: l.input[start:l.pos], pos: start}, nil
+	case unicode.IsDigit(rune(c)):
+		l.digits()
+		// a single fraction, so 1.2.3 is not a number
+		if l.pos+1 < len(l.input) && l.input[l.pos] == '.' && unicode.IsDigit(rune(l.input[l.pos+1])) {
+			l.pos++
+			l.digits()
+		}
+		return token{kind: tokenNumber, text: l.input[start:l.pos], pos: start}, nil
+	case c == '\'' || c == '"':
+		var sb strings.Builder
+		l.pos++
+		for l.pos < len(l.input) && l.input[l.pos] != c {
+			if l.input[l.pos] == '\\' && l.pos+1 < len(l.input) {
+				l.pos++
+			}
+			sb.WriteByte(l.input[l.pos])
+			l.pos++
+		}
+		if l.pos >= len(l.input) {
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+		}
+		l.pos++
+		return token{kind: tokenString, text: sb.String(), pos: start}, nil
+	}
+
+	for _, p := range punctuation {
+		if strings.HasPrefix(l.input[l.pos:], p) {
+			l.pos += len(p)
+			return token{kind: tokenPunct, text: p, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected character %q at position %d", c, start)
+}
+
+type parser struct {
+	lexer lexer
+	token token
+	err   error
+}
+
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	p.token, p.err = p.lexer.next()
+	if p.err != nil {
+		p.token = token{kind: tokenEOF, text: "end of expression", pos: p.lexer.pos}
+	}
+}
+
+func (p *parser) is(text string) bool {
+	return p.token.kind == tokenPunct && p.token.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.unexpected()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("unexpected %s at position %d", p.token.text, p.token.pos)
+}
+
+func (p *parser) parseExpression() (node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.is("?") {
+		return cond, nil
+	}
+	p.next()
+	then, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionalNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+// binary operators by precedence, lowest first
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binaryOperator(level int) (string, bool) {
+	for _, op := range precedence[level] {
+		if (op == "in" && p.token.kind == tokenIdent && p.token.text == "in") || p.is(op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.binaryOperator(level)
+		if !ok {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.is("!") || p.is("-") {
+		op := p.token.text
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parseMember()
+}
+
+func (p *parser) parseMember() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.is("."):
+			p.next()
+			if p.token.kind != tokenIdent {
+				return nil, p.unexpected()
+			}
+			name := p.token.text
+			p.next()
+			if p.is("(") {
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+				n, err = newMethodNode(n, name, args)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				n = &selectNode{operand: n, field: name}
+			}
+		case p.is("["):
+			p.next()
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{operand: n, index: index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parseArguments() ([]node, error) {
+	p.next() // (
+	args := []node{}
+	for !p.is(")") {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.token
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", t.text, t.pos)
+		}
+		return &literalNode{value: f}, nil
+	case tokenString:
+		p.next()
+		return &literalNode{value: t.text}, nil
+	case tokenIdent:
+		p.next()
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.is("(") {
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+			return newFunctionNode(t.text, args)
+		}
+		return &identNode{name: t.text}, nil
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			p.next()
+			n, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			args, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{items: args}, nil
+		}
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseList() ([]node, error) {
+	p.next() // [
+	items := []node{}
+	for !p.is("]") {
+		item, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+	return items, p.expect("]")
+}
+
+type node interface {
+	eval(e *env) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(e *env) (interface{}, error) {
+	return n.value, nil
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(e *env) (interface{}, error) {
+	list := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(e *env) (interface{}, error) {
+	v, ok := e.lookup(n.name)
+	if !ok {
+		return nil, fmt.Errorf("undeclared reference to %s", n.name)
+	}
+	return v, nil
+}
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+func (n *selectNode) eval(e *env) (interface{}, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch o := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return o[n.field], nil
+	}
+	return nil, fmt.Errorf("cannot select %s of %s", n.field, typeName(v))
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+func (n *indexNode) eval(e *env) (interface{}, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	i, err := n.index.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch o := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		key, ok := i.(string)
+		if !ok {
+			return nil, fmt.Errorf("map index must be a string, got %s", typeName(i))
+		}
+		return o[key], nil
+	case []interface{}:
+		f, ok := i.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("list index must be an integer, got %v", i)
+		}
+		if f < 0 || int(f) >= len(o) {
+			return nil, nil
+		}
+		return o[int(f)], nil
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(v))
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(e *env) (interface{}, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator ! requires a bool, got %s", typeName(v))
+		}
+		return !b, nil
+	default:
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operator - requires a number, got %s", typeName(v))
+		}
+		return -f, nil
+	}
+}
+
+type conditionalNode struct {
+	cond, then, otherwise node
+}
+
+func (n *conditionalNode) eval(e *env) (interface{}, error) {
+	c, err := n.cond.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := c.(bool)
+	if !ok {
+		return nil, fmt.Errorf("condition requires a bool, got %s", typeName(c))
+	}
+	if b {
+		return n.then.eval(e)
+	}
+	return n.otherwise.eval(e)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(e *env) (interface{}, error) {
+	l, err := n.left.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// && and || short circuit
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires bools, got %s", n.op, typeName(l))
+		}
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		r, err := n.right.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires bools, got %s", n.op, typeName(r))
+		}
+		return rb, nil
+	}
+
+	r, err := n.right.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		switch c := r.(type) {
+		case []interface{}:
+			for _, item := range c {
+				if equal(l, item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			key, ok := l.(string)
+			if !ok {
+				return nil, fmt.Errorf("map keys are strings, got %s", typeName(l))
+			}
+			_, found := c[key]
+			return found, nil
+		case nil:
+			return false, nil
+		}
+		return nil, fmt.Errorf("operator in requires a list or a map, got %s", typeName(r))
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "+":
+		if ls, ok := l.(string); ok {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+		if ll, ok := l.([]interface{}); ok {
+			if rl, ok := r.([]interface{}); ok {
+				return append(append([]interface{}{}, ll...), rl...), nil
+			}
+		}
+	}
+
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator %s is not defined for %s and %s", n.op, typeName(l), typeName(r))
+	}
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	default:
+		if rf == 0 {
+			return nil, fmt.Errorf("modulus by zero")
+		}
+		return math.Mod(lf, rf), nil
+	}
+}
+
+// functionNode - Global function call
+type functionNode struct {
+	name string
+	args []node
+}
+
+func newFunctionNode(name string, args []node) (node, error) {
+	switch name {
+	case "has":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("has requires one argument")
+		}
+		if _, ok := args[0].(*selectNode); !ok {
+			if _, ok := args[0].(*indexNode); !ok {
+				return nil, fmt.Errorf("has requires a field selection")
+			}
+		}
+	case "size", "string", "int":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s requires one argument", name)
+		}
+	default:
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+	return &functionNode{name: name, args: args}, nil
+}
+
+func (n *functionNode) eval(e *env) (interface{}, error) {
+	v, err := n.args[0].eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch n.name {
+	case "has":
+		return v != nil, nil
+	case "size":
+		return size(v)
+	case "string":
+		return toString(v), nil
+	default:
+		switch t := v.(type) {
+		case float64:
+			return math.Trunc(t), nil
+		case string:
+			f, err := strconv.ParseFloat(t, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to int", t)
+			}
+			return math.Trunc(f), nil
+		}
+		return nil, fmt.Errorf("cannot convert %s to int", typeName(v))
+	}
+}
+
+// methodNode - Method call on a value, including the list macros
+type methodNode struct {
+	operand node
+	name    string
+	args    []node
+	// variable of the list macros
+	variable string
+	// re is the compiled regular expression of matches, when its argument is a literal
+	re *regexp.Regexp
+}
+
+func newMethodNode(operand node, name string, args []node) (node, error) {
+	m := &methodNode{operand: operand, name: name, args: args}
+	switch name {
+	case "exists", "all", "filter":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s requires a variable and a predicate", name)
+		}
+		ident, ok := args[0].(*identNode)
+		if !ok {
+			return nil, fmt.Errorf("the first argument of %s must be a variable name", name)
+		}
+		m.variable = ident.name
+	case "startsWith", "endsWith", "contains", "matches":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s requires one argument", name)
+		}
+		// an invalid literal is reported when the expression is evaluated
+		if l, ok := args[0].(*literalNode); ok && name == "matches" {
+			if pattern, ok := l.value.(string); ok {
+				m.re, _ = regexp.Compile(pattern)
+			}
+		}
+	case "lowerAscii", "upperAscii", "size":
+		if len(args) != 0 {
+			return nil, fmt.Errorf("%s requires no arguments", name)
+		}
+	default:
+		return nil, fmt.Errorf("unknown method %s", name)
+	}
+	return m, nil
+}
+
+func (n *methodNode) eval(e *env) (interface{}, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.name {
+	case "exists", "all", "filter":
+		return n.evalMacro(e, v)
+	case "size":
+		return size(v)
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a string, got %s", n.name, typeName(v))
+	}
+	if n.name == "lowerAscii" {
+		return strings.ToLower(s), nil
+	}
+	if n.name == "upperAscii" {
+		return strings.ToUpper(s), nil
+	}
+
+	a, err := n.args[0].eval(e)
+	if err != nil {
+		return nil, err
+	}
+	arg, ok := a.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a string argument, got %s", n.name, typeName(a))
+	}
+	switch n.name {
+	case "startsWith":
+		return strings.HasPrefix(s, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(s, arg), nil
+	case "contains":
+		return strings.Contains(s, arg), nil
+	default:
+		if n.re != nil {
+			return n.re.MatchString(s), nil
+		}
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", arg, err)
+		}
+		return re.MatchString(s), nil
+	}
+}
+
+func (n *methodNode) evalMacro(e *env, v interface{}) (interface{}, error) {
+	var items []interface{}
+	switch t := v.(type) {
+	case nil:
+	case []interface{}:
+		items = t
+	case map[string]interface{}:
+		for k := range t {
+			items = append(items, k)
+		}
+	default:
+		return nil, fmt.Errorf("%s requires a list or a map, got %s", n.name, typeName(v))
+	}
+
+	filtered := []interface{}{}
+	for _, item := range items {
+		r, err := n.args[1].eval(&env{vars: map[string]interface{}{n.variable: item}, parent: e})
+		if err != nil {
+			return nil, err
+		}
+		b, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("the predicate of %s must return a bool, got %s", n.name, typeName(r))
+		}
+		switch {
+		case n.name == "exists" && b:
+			return true, nil
+		case n.name == "all" && !b:
+			return false, nil
+		case n.name == "filter" && b:
+			filtered = append(filtered, item)
+		}
+	}
+
+	switch n.name {
+	case "exists":
+		return false, nil
+	case "all":
+		return true, nil
+	default:
+		return filtered, nil
+	}
+}
+
+func equal(l, r interface{}) bool {
+	return reflect.DeepEqual(l, r)
+}
+
+func compare(l, r interface{}) (int, error) {
+	switch lv := l.(type) {
+	case float64:
+		if rv, ok := r.(float64); ok {
+			switch {
+			case lv < rv:
+				return -1, nil
+			case lv > rv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if rv, ok := r.(string); ok {
+			return strings.Compare(lv, rv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s and %s", typeName(l), typeName(r))
+}
+
+func size(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case nil:
+		return float64(0), nil
+	case string:
+		return float64(len(t)), nil
+	case []interface{}:
+		return float64(len(t)), nil
+	case map[string]interface{}:
+		return float64(len(t)), nil
+	}
+	return nil, fmt.Errorf("size is not defined for %s", typeName(v))
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rules
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testResource = `{
+	"id": "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st",
+	"name": "st",
+	"type": "Microsoft.Storage/storageAccounts",
+	"tags": {"env": "prod"},
+	"sku": {"name": "Standard_LRS"},
+	"properties": {
+		"minimumTlsVersion": "TLS1_2",
+		"allowSharedKeyAccess": false,
+		"networkAcls": {
+			"ipRules": [{"value": "10.0.0.1"}, {"value": "0.0.0.0/0"}]
+		}
+	}
+}`
+
+func testVars(t *testing.T) map[string]interface{} {
+	t.Helper()
+	resource := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(testResource), &resource); err != nil {
+		t.Fatal(err)
+	}
+	return map[string]interface{}{"resource": resource, "diagnosticSettings": true}
+}
+
+func TestExpression_Eval(t *testing.T) {
+	tests := map[string]interface{}{
+		`resource.properties.minimumTlsVersion == 'TLS1_2'`:                                true,
+		`resource.properties.minimumTlsVersion != "TLS1_2"`:                                false,
+		`resource.properties.allowSharedKeyAccess != false`:                                false,
+		`resource.properties.missing.nested == null`:                                       true,
+		`has(resource.properties.minimumTlsVersion) && !has(resource.properties.foo)`:      true,
+		`resource.tags['env'] in ['prod', 'production']`:                                   true,
+		`'env' in resource.tags`:                                                           true,
+		`resource.sku.name.startsWith('Standard') && resource.sku.name.endsWith('LRS')`:    true,
+		`resource.name.matches('^st[a-z]*$')`:                                              true,
+		`resource.sku.name.lowerAscii()`:                                                   "standard_lrs",
+		`size(resource.properties.networkAcls.ipRules)`:                                    float64(2),
+		`resource.properties.networkAcls.ipRules.exists(r, r.value == '0.0.0.0/0')`:        true,
+		`resource.properties.networkAcls.ipRules.all(r, r.value.contains('.'))`:            true,
+		`resource.properties.networkAcls.ipRules.filter(r, r.value.endsWith('/0')).size()`: float64(1),
+		`resource.properties.networkAcls.ipRules[0].value`:                                 "10.0.0.1",
+		`resource.properties.missing.exists(r, true)`:                                      false,
+		`diagnosticSettings ? 'yes' : 'no'`:                                                "yes",
+		`1 + 2 * 3 >= 7 && -1 < 0`:                                                         true,
+		`'a' + "b"`:                                                                        "ab",
+		`int('12') % 5`:                                                                    float64(2),
+		`int('3.7') + int(-1.5)`:                                                           float64(2),
+		`has(resource.properties.missing) || has(resource.tags.env)`:                       true,
+		`string(42)`:                "42",
+		`[1, 2] == [1, 2]`:          true,
+		`false || (true && !false)`: true,
+	}
+
+	vars := testVars(t)
+	for source, want := range tests {
+		e, err := Compile(source)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", source, err)
+			continue
+		}
+		got, err := e.Eval(vars)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", source, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Eval(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestExpression_CompileErrors(t *testing.T) {
+	invalid := []string{
+		``,
+		`resource.`,
+		`resource.name ==`,
+		`(true`,
+		`'unterminated`,
+		`unknown(resource)`,
+		`resource.name.unknown()`,
+		`resource.list.exists('x', true)`,
+		`has(resource)`,
+		`true true`,
+		`resource # name`,
+		`1.2.3`,
+		`1.`,
+	}
+	for _, source := range invalid {
+		if _, err := Compile(source); err == nil {
+			t.Errorf("Compile(%q): expected an error", source)
+		}
+	}
+}
+
+func TestExpression_EvalErrors(t *testing.T) {
+	invalid := []string{
+		`undeclared == 1`,
+		`resource.name < 1`,
+		`resource.name && true`,
+		`resource.missing.startsWith('a')`,
+		`resource.name.matches('[')`,
+		`1 / 0`,
+	}
+	vars := testVars(t)
+	for _, source := range invalid {
+		e, err := Compile(source)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", source, err)
+			continue
+		}
+		if _, err := e.Eval(vars); err == nil {
+			t.Errorf("Eval(%q): expected an error", source)
+		}
+	}
+}
+
+func TestExpression_EvalBool(t *testing.T) {
+	e, err := Compile(`resource.name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.EvalBool(testVars(t)); err == nil {
+		t.Error("expected an error for an expression that does not return a bool")
+	}
+}
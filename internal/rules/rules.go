@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package rules loads declarative AZQR rules: YAML definitions with an expression
+// evaluated against the ARM JSON of each resource.
+package rules
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed builtin
+var builtinFiles embed.FS
+
+// Variables of the rule expressions
+const (
+	// VarResource is the ARM JSON of the resource
+	VarResource = "resource"
+	// VarDiagnosticSettings is true when the resource has diagnostic settings
+	VarDiagnosticSettings = "diagnosticSettings"
+	// VarPrivateEndpoint is true when the resource has a private endpoint
+	VarPrivateEndpoint = "privateEndpoint"
+)
+
+// Rule - Declarative rule definition
+type Rule struct {
+	RecommendationID string `yaml:"id"`
+	ResourceType     string `yaml:"resourceType"`
+	Category         string `yaml:"category"`
+	Impact           string `yaml:"impact"`
+	Recommendation   string `yaml:"recommendation"`
+	LearnMoreUrl     string `yaml:"learnMoreUrl"`
+	// Expression returns true when the resource is not compliant
+	Expression string `yaml:"expression"`
+	// Result is an optional expression whose value is reported in the Result column
+	Result string `yaml:"result,omitempty"`
+	// Applies is an optional expression. The rule is not applicable to the resources for which it returns false
+	Applies string `yaml:"applies,omitempty"`
+	// Source is the file the rule was loaded from
+	Source string `yaml:"-"`
+
+	expression *Expression
+	result     *Expression
+	applies    *Expression
+}
+
+// Builtin returns the rules embedded in azqr
+func Builtin() ([]*Rule, error) {
+	fsys, err := fs.Sub(builtinFiles, "builtin")
+	if err != nil {
+		return nil, err
+	}
+	return Load(fsys)
+}
+
+// LoadDir loads the rules of the *.yaml and *.yml files of a directory and its subdirectories
+func LoadDir(dir string) ([]*Rule, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("rules directory %s is not a directory", dir)
+	}
+
+	rules, err := Load(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		r.Source = filepath.Join(dir, r.Source)
+	}
+	return rules, nil
+}
+
+// Load loads the rules of the *.yaml and *.yml files of a filesystem.
+// Each file contains a list of rules. Rule ids must be unique
+func Load(fsys fs.FS) ([]*Rule, error) {
+	rules := []*Rule{}
+	ids := map[string]string{}
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (!strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml")) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		var definitions []*Rule
+		if err := yaml.Unmarshal(content, &definitions); err != nil {
+			return fmt.Errorf("failed to parse rules file %s: %w", path, err)
+		}
+
+		for _, r := range definitions {
+			r.Source = path
+			if err := r.compile(); err != nil {
+				return fmt.Errorf("invalid rule in %s: %w", path, err)
+			}
+			if other, ok := ids[r.RecommendationID]; ok {
+				return fmt.Errorf("rule %s is defined in %s and %s", r.RecommendationID, other, path)
+			}
+			ids[r.RecommendationID] = path
+			rules = append(rules, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// compile validates the rule and compiles its expressions
+func (r *Rule) compile() error {
+	if r.RecommendationID == "" {
+		return fmt.Errorf("rule without id")
+	}
+	if r.ResourceType == "" || r.Recommendation == "" || r.Expression == "" {
+		return fmt.Errorf("rule %s requires a resourceType, a recommendation and an expression", r.RecommendationID)
+	}
+
+	var err error
+	if r.expression, err = Compile(r.Expression); err != nil {
+		return fmt.Errorf("rule %s: %w", r.RecommendationID, err)
+	}
+	if r.Result != "" {
+		if r.result, err = Compile(r.Result); err != nil {
+			return fmt.Errorf("rule %s: %w", r.RecommendationID, err)
+		}
+	}
+	if r.Applies != "" {
+		if r.applies, err = Compile(r.Applies); err != nil {
+			return fmt.Errorf("rule %s: %w", r.RecommendationID, err)
+		}
+	}
+	return nil
+}
+
+// Applicable returns false when the applies expression of the rule returns false for the variables of a resource
+func (r *Rule) Applicable(vars map[string]interface{}) (bool, error) {
+	if r.applies == nil {
+		return true, nil
+	}
+	applies, err := r.applies.EvalBool(vars)
+	if err != nil {
+		return false, fmt.Errorf("rule %s: %w", r.RecommendationID, err)
+	}
+	return applies, nil
+}
+
+// Evaluate evaluates the rule against the variables of a resource.
+// It returns true when the resource is not compliant, and the value of the result expression
+func (r *Rule) Evaluate(vars map[string]interface{}) (bool, string, error) {
+	broken, err := r.expression.EvalBool(vars)
+	if err != nil {
+		return false, "", fmt.Errorf("rule %s: %w", r.RecommendationID, err)
+	}
+
+	result := ""
+	if r.result != nil {
+		v, err := r.result.Eval(vars)
+		if err != nil {
+			return false, "", fmt.Errorf("rule %s: %w", r.RecommendationID, err)
+		}
+		result = toString(v)
+	}
+	return broken, result, nil
+}
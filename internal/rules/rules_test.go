@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBuiltin(t *testing.T) {
+	rules, err := Builtin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) == 0 {
+		t.Error("expected built-in rules")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+- id: contoso-001
+  resourceType: Microsoft.Storage/storageAccounts
+  category: Security
+  impact: High
+  recommendation: Storage Account should use TLS 1.2
+  expression: resource.properties.minimumTlsVersion != 'TLS1_2'
+  result: resource.properties.minimumTlsVersion
+`
+	if err := os.WriteFile(filepath.Join(dir, "storage.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a rule"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(rules))
+	}
+	if rules[0].Source != filepath.Join(dir, "storage.yaml") {
+		t.Errorf("unexpected source %s", rules[0].Source)
+	}
+
+	broken, result, err := rules[0].Evaluate(testVars(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if broken || result != "TLS1_2" {
+		t.Errorf("expected a compliant resource with result TLS1_2, got %v %q", broken, result)
+	}
+
+	if _, err := LoadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"yaml": {"a.yaml": {Data: []byte("not: [a list")}},
+		"expression": {"a.yaml": {Data: []byte(`
+- id: r1
+  resourceType: Microsoft.Storage/storageAccounts
+  recommendation: r1
+  expression: resource.name ==`)}},
+		"missing expression": {"a.yaml": {Data: []byte(`
+- id: r1
+  resourceType: Microsoft.Storage/storageAccounts
+  recommendation: r1`)}},
+		"duplicate": {
+			"a.yaml": {Data: []byte("- {id: r1, resourceType: t, recommendation: r1, expression: 'true'}")},
+			"b.yml":  {Data: []byte("- {id: r1, resourceType: t, recommendation: r1, expression: 'false'}")},
+		},
+	}
+	for name, fsys := range tests {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
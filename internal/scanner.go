@@ -5,22 +5,33 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Azure/azqr/internal/cassette"
+	"github.com/Azure/azqr/internal/clouds"
+	"github.com/Azure/azqr/internal/graph"
 	"github.com/Azure/azqr/internal/renderers"
 	"github.com/Azure/azqr/internal/renderers/csv"
 	"github.com/Azure/azqr/internal/renderers/excel"
+	"github.com/Azure/azqr/internal/renderers/html"
 	"github.com/Azure/azqr/internal/renderers/json"
+	"github.com/Azure/azqr/internal/renderers/junit"
+	"github.com/Azure/azqr/internal/renderers/markdown"
+	"github.com/Azure/azqr/internal/renderers/sarif"
+	"github.com/Azure/azqr/internal/renderers/scripts"
 	"github.com/Azure/azqr/internal/scanners"
-	"github.com/rs/zerolog"
+	"github.com/Azure/azqr/internal/state"
 	"github.com/rs/zerolog/log"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 )
 
 type (
@@ -33,40 +44,137 @@ type (
 		Advisor                 bool
 		Cost                    bool
 		Mask                    bool
+		Xlsx                    bool
 		Csv                     bool
 		Json                    bool
-		Debug                   bool
+		Html                    bool
+		Sarif                   bool
 		ScannerKeys             []string
 		ForceAzureCliCredential bool
+		Credential              azcore.TokenCredential
 		Filters                 *scanners.Filters
 		UseAzqrRecommendations  bool
 		UseAprlRecommendations  bool
+		Parallelism             int
+		MaxConcurrentRequests   int
+		RecordDir               string
+		ReplayDir               string
+		Checkpoint              bool
+		ResumeFile              string
+		Cloud                   string
+		Auth                    string
+		ClientID                string
+		TenantID                string
+		ClientCertificate       string
+		TenantIDs               []string
+		Since                   string
+		AzqrRulesDirs           []string
+		RulesDirs               []string
+		PolicyDir               string
+		NamingPolicy            string
+		TagPolicy               string
+		WorkloadsFile           string
+		Remediation             bool
+		Junit                   bool
+		Markdown                bool
+		Baseline                string
 	}
 
 	Scanner struct{}
 )
 
-func (sc Scanner) Scan(params *ScanParams) {
-	// Default level for this example is info, unless debug flag is present
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	if params.Debug {
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-		log.Debug().Msg("Debug logging enabled")
+// Scan scans the Azure resources in scope and returns the report data. Nothing is rendered.
+// Errors raised after the subscriptions are listed do not stop the scan: they are collected in the
+// report data, so a partial report is still produced.
+func (sc Scanner) Scan(ctx context.Context, params *ScanParams) (*renderers.ReportData, error) {
+	// load the declarative rules and policies, evaluated with the Go rules of the scanners
+	declarative, err := scanners.LoadDeclarativeRules(params.AzqrRulesDirs)
+	if err != nil {
+		return nil, err
 	}
+	policies, err := scanners.LoadPolicies(ctx, params.PolicyDir)
+	if err != nil {
+		return nil, err
+	}
+	naming, err := scanners.LoadNamingPolicy(params.NamingPolicy)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := scanners.LoadTagPolicy(params.TagPolicy)
+	if err != nil {
+		return nil, err
+	}
+	rules := &scanners.Rules{Declarative: declarative, Policies: policies, Naming: naming, Tags: tags}
+
+	// load the findings of the baseline scan, to report the new and resolved findings
+	var baseline []renderers.Finding
+	if params.Baseline != "" {
+		if baseline, err = renderers.LoadFindings(params.Baseline); err != nil {
+			return nil, err
+		}
+	}
+
+	scan := sc.scanTenant
+	if len(params.TenantIDs) > 0 {
+		scan = sc.scanTenants
+	}
+	data, err := scan(ctx, params, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// reported once, after all the tenants are scanned
+	if params.Filters != nil {
+		for _, p := range params.Filters.Azqr.UnmatchedPatterns() {
+			log.Warn().Msgf("Filter pattern %s did not match anything", p)
+		}
+	}
+	data.Baseline = baseline
+	return data, nil
+}
 
+// scanTenant scans the resources of a single tenant
+func (sc Scanner) scanTenant(ctx context.Context, params *ScanParams, rules *scanners.Rules) (*renderers.ReportData, error) {
 	// generate output file name
 	outputFile := sc.generateOutputFileName(params.OutputName)
 
 	// load filters
 	filters := params.Filters
+	if filters == nil {
+		var err error
+		filters, err = scanners.LoadFilters("", params.ScannerKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// load the workload definitions
+	var workloads []*scanners.Workload
+	if params.WorkloadsFile != "" {
+		var err error
+		workloads, err = scanners.LoadWorkloads(params.WorkloadsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// load the custom rule packs
+	rulePacks, err := LoadRulePacks(params.RulesDirs)
+	if err != nil {
+		return nil, err
+	}
 
 	// validate input
 	if params.ManagementGroupID != "" && (params.SubscriptionID != "" || params.ResourceGroup != "") {
-		log.Fatal().Msg("Management Group name cannot be used with a Subscription Id or Resource Group name")
+		return nil, errors.New("management group id cannot be used with a subscription id or resource group name")
 	}
 
 	if params.SubscriptionID == "" && params.ResourceGroup != "" {
-		log.Fatal().Msg("Resource Group name can only be used with a Subscription Id")
+		return nil, errors.New("resource group name can only be used with a subscription id")
+	}
+
+	if params.RecordDir != "" && params.ReplayDir != "" {
+		return nil, errors.New("record and replay cannot be used together")
 	}
 
 	if params.SubscriptionID != "" {
@@ -79,16 +187,52 @@ func (sc Scanner) Scan(params *ScanParams) {
 
 	serviceScanners := filters.Azqr.Scanners
 
+	azureCloud, err := clouds.Get(params.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	// load the state of an interrupted scan and save the progress of this one
+	previous, checkpoint, outputFile, err := sc.newCheckpoint(params, filters, outputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// incremental scan: the previous scan time and results
+	var since time.Time
+	var baseline *state.State
+	if params.Since != "" {
+		if params.ResumeFile != "" {
+			return nil, errors.New("since and resume cannot be used together")
+		}
+		since, baseline, err = sc.parseSince(params.Since, previous.Scope)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// create Azure credentials
-	cred := sc.newAzureCredential(params.ForceAzureCliCredential)
+	cred := params.Credential
+	if params.ReplayDir != "" {
+		// replayed requests never reach Azure
+		cred = cassette.Credential{}
+	}
+	if cred == nil {
+		cred, err = sc.newAzureCredential(ctx, params, azureCloud)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// create a cancelable context
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// fail before scanning if the credential cannot authenticate
+	if err := sc.validateCredential(ctx, cred, azureCloud); err != nil {
+		return nil, err
+	}
 
 	// create ARM client options
 	clientOptions := &arm.ClientOptions{
 		ClientOptions: policy.ClientOptions{
+			Cloud: azureCloud.Configuration,
 			Retry: policy.RetryOptions{
 				RetryDelay:    20 * time.Millisecond,
 				MaxRetries:    3,
@@ -97,39 +241,108 @@ func (sc Scanner) Scan(params *ScanParams) {
 		},
 	}
 
+	// throttle the Resource Graph and ARM batch requests with the quota reported by Azure
+	clientOptions.PerRetryPolicies = append(clientOptions.PerRetryPolicies, graph.NewRateLimiter())
+
+	// limit the number of in-flight ARM requests across all subscriptions and scanners
+	if params.MaxConcurrentRequests > 0 {
+		clientOptions.PerRetryPolicies = append(clientOptions.PerRetryPolicies, newConcurrencyPolicy(params.MaxConcurrentRequests))
+	}
+
+	// record or replay the ARM and Resource Graph traffic
+	if params.RecordDir != "" {
+		recorder, err := cassette.NewRecorder(params.RecordDir, nil)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Msgf("Recording ARM and Resource Graph traffic to %s", params.RecordDir)
+		clientOptions.Transport = recorder
+	} else if params.ReplayDir != "" {
+		player, err := cassette.NewPlayer(params.ReplayDir)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Msgf("Replaying ARM and Resource Graph traffic from %s", params.ReplayDir)
+		clientOptions.Transport = player
+	}
+
 	// list subscriptions. Key is subscription ID, value is subscription name
 	var subscriptions map[string]string
 	if params.ManagementGroupID != "" {
 		managementGroupScanner := scanners.ManagementGroupsScanner{}
-		subscriptions = managementGroupScanner.ListSubscriptions(ctx, cred, params.ManagementGroupID, filters, clientOptions)
+		subscriptions, err = managementGroupScanner.ListSubscriptions(ctx, cred, params.ManagementGroupID, filters, clientOptions)
 	} else {
 		subscriptionScanner := scanners.SubcriptionScanner{}
-		subscriptions = subscriptionScanner.ListSubscriptions(ctx, cred, params.SubscriptionID, filters, clientOptions)
+		subscriptions, err = subscriptionScanner.ListSubscriptions(ctx, cred, params.SubscriptionID, filters, clientOptions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// exclude the resources by their tags before any scanner runs
+	resourceScanner := scanners.ResourceScanner{}
+	if err := resourceScanner.LoadTagFilters(ctx, cred, subscriptions, filters, clientOptions); err != nil {
+		return nil, fmt.Errorf("failed to apply the tag filters: %w", err)
+	}
+
+	// get the resources changed since the previous scan. Nil means a full scan
+	var incremental *incrementalScan
+	if params.Since != "" {
+		incremental = sc.newIncrementalScan(ctx, cred, clientOptions, subscriptions, serviceScanners, since, baseline)
 	}
 
 	// initialize scanners
-	defenderScanner := scanners.DefenderScanner{}
-	pipScanner := scanners.PublicIPScanner{}
-	peScanner := scanners.PrivateEndpointScanner{}
+	defenderScanner := scanners.DefenderScanner{Cloud: azureCloud}
 	diagnosticsScanner := scanners.DiagnosticSettingsScanner{}
 	advisorScanner := scanners.AdvisorScanner{}
-	costScanner := scanners.CostScanner{}
 	diagResults := map[string]bool{}
 
 	// initialize report data
 	reportData := renderers.NewReportData(outputFile, params.Mask)
+	reportData.Remediation = params.Remediation
+	reportData.ScannedAt = previous.StartedAt
 
 	// get the APRL scan results
 	aprlScanner := NewAprlScanner(serviceScanners, filters, subscriptions)
-	reportData.Recommendations, reportData.Aprl = aprlScanner.Scan(ctx, cred)
+	aprlScanner.AddRulePacks(rulePacks...)
+	if incremental != nil {
+		aprlScanner.subscriptions = incremental.changedSubscriptions(subscriptions)
+		aprlScanner.resourceTypes = incremental.resourceTypes
+	}
+	var aprlErrors []scanners.ScanError
+	reportData.Recommendations, reportData.Aprl, aprlErrors = aprlScanner.Scan(ctx, cred, clientOptions, previous, checkpoint)
+	reportData.Errors = append(reportData.Errors, aprlErrors...)
+
+	// carry over the APRL results of the unchanged resources
+	if incremental != nil {
+		carried := incremental.carriedAprl()
+		for _, r := range carried {
+			if !filters.Azqr.IsServiceExcluded(r.ResourceID) {
+				reportData.Aprl = append(reportData.Aprl, r)
+			}
+		}
+		saveCheckpoint(checkpoint, func(s *state.State) { s.CarriedAprl = carried })
+	}
 
-	resourceScanner := scanners.ResourceScanner{}
-	reportData.Resources, reportData.ExludedResources = resourceScanner.GetAllResources(ctx, cred, subscriptions, filters)
+	if previous.Phases[state.PhaseResources] {
+		reportData.Resources, reportData.ExludedResources = previous.Resources, previous.ExcludedResources
+	} else {
+		resources, excludedResources, err := resourceScanner.GetAllResources(ctx, cred, subscriptions, filters, clientOptions)
+		if err != nil {
+			reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseResources, "", "", "Resource Graph", err))
+		} else {
+			reportData.Resources, reportData.ExludedResources = resources, excludedResources
+			saveCheckpoint(checkpoint, func(s *state.State) {
+				s.Resources, s.ExcludedResources = resources, excludedResources
+				s.Phases[state.PhaseResources] = true
+			})
+		}
+	}
 
 	// For each service scanner, get the recommendations list
 	if params.UseAzqrRecommendations {
 		for _, s := range serviceScanners {
-			for i, r := range s.GetRecommendations() {
+			for i, r := range scanners.ScannerRecommendations(s, rules.Declarative) {
 				if filters.Azqr.IsRecommendationExcluded(r.RecommendationID) {
 					continue
 				}
@@ -146,111 +359,380 @@ func (sc Scanner) Scan(params *ScanParams) {
 			}
 		}
 
-		// scan diagnostic settings
-		err := diagnosticsScanner.Init(ctx, cred, clientOptions)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to initialize diagnostic settings scanner")
+		// scan diagnostic settings. Resources of failed batches are reported without diagnostic settings
+		if previous.Phases[state.PhaseDiagnostics] {
+			diagResults = previous.Diagnostics
+		} else if err := diagnosticsScanner.Init(ctx, cred, clientOptions); err != nil {
+			reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseDiagnostics, "", "", "Diagnostic Settings", err))
+		} else {
+			resourceIDs := reportData.ResourceIDs()
+			if incremental != nil {
+				resourceIDs = incremental.resourceIDs(reportData.Resources)
+			}
+			results, err := diagnosticsScanner.Scan(resourceIDs)
+			if err != nil {
+				reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseDiagnostics, "", "", "Diagnostic Settings", err))
+			} else {
+				saveCheckpoint(checkpoint, func(s *state.State) {
+					s.Diagnostics = results
+					s.Phases[state.PhaseDiagnostics] = true
+				})
+			}
+			if results != nil {
+				diagResults = results
+			}
 		}
-
-		diagResults = diagnosticsScanner.Scan(reportData.ResourceIDs())
 	}
 
-	// scan each subscription with AZQR scanners
-	for sid, sn := range subscriptions {
-		config := &scanners.ScannerConfig{
-			Ctx:              ctx,
-			SubscriptionID:   sid,
-			SubscriptionName: sn,
-			Cred:             cred,
-			ClientOptions:    clientOptions,
-		}
+	// scan the subscriptions with AZQR scanners, using a bounded pool of workers
+	subscriptionIDs := make([]string, 0, len(subscriptions))
+	for sid := range subscriptions {
+		subscriptionIDs = append(subscriptionIDs, sid)
+	}
+	sort.Strings(subscriptionIDs)
 
-		if params.UseAzqrRecommendations {
-			// scan private endpoints
-			peResults := peScanner.Scan(config)
+	parallelism := params.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
 
-			// scan public IPs
-			pips := pipScanner.Scan(config)
+	jobs := make(chan int, len(subscriptionIDs))
+	subscriptionResults := make([]subscriptionScanResult, len(subscriptionIDs))
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				sid := subscriptionIDs[i]
+				if sub, ok := previous.Subscriptions[sid]; ok {
+					log.Info().Msgf("Skipping subscription %s. Already scanned", subscriptions[sid])
+					subscriptionResults[i] = subscriptionScanResult{azqr: sub.Azqr, costs: sub.Costs}
+					continue
+				}
 
-			// initialize scan context
-			scanContext := scanners.ScanContext{
-				Filters:             filters,
-				PrivateEndpoints:    peResults,
-				DiagnosticsSettings: diagResults,
-				PublicIPs:           pips,
+				config := &scanners.ScannerConfig{
+					Ctx:              ctx,
+					SubscriptionID:   sid,
+					SubscriptionName: subscriptions[sid],
+					Cred:             cred,
+					ClientOptions:    clientOptions,
+				}
+				subscriptionScanners := serviceScanners
+				if incremental != nil {
+					subscriptionScanners = incremental.serviceScanners(sid, serviceScanners)
+				}
+				res := sc.scanSubscription(config, params, rules, subscriptionScanners, filters, diagResults)
+				subscriptionResults[i] = res
+
+				// subscriptions with errors are not saved, so they are scanned again when the scan is resumed
+				if len(res.errors) == 0 {
+					saveCheckpoint(checkpoint, func(s *state.State) {
+						s.Subscriptions[sid] = &state.Subscription{Azqr: res.azqr, Costs: res.costs}
+					})
+				}
 			}
+		}()
+	}
 
-			// scan each resource group
-			ch := make(chan []scanners.AzqrServiceResult, len(serviceScanners))
-
-			for _, s := range serviceScanners {
-				err := s.Init(config)
-				if err != nil {
-					log.Fatal().Err(err).Msg("Failed to initialize scanner")
-				}
+	for i := range subscriptionIDs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// merge the results in subscription order, so the output is deterministic
+	for _, res := range subscriptionResults {
+		reportData.Errors = append(reportData.Errors, res.errors...)
+		reportData.Azqr = append(reportData.Azqr, res.azqr...)
+		if res.costs != nil && len(res.costs.Items) > 0 {
+			reportData.Cost.From = res.costs.From
+			reportData.Cost.To = res.costs.To
+			reportData.Cost.Items = append(reportData.Cost.Items, res.costs.Items...)
+		}
+	}
 
-				go func(s scanners.IAzureScanner) {
-					res, err := sc.retry(3, 10*time.Millisecond, s, &scanContext)
-					if err != nil {
-						cancel()
-						log.Fatal().Err(err).Msg("Failed to scan")
-					}
-					ch <- res
-				}(s)
+	// carry over the AZQR results of the unchanged resources
+	if incremental != nil {
+		carried := incremental.carriedAzqr()
+		for _, r := range carried {
+			if !filters.Azqr.IsServiceExcluded(r.ResourceID()) {
+				reportData.Azqr = append(reportData.Azqr, r)
 			}
+		}
+		saveCheckpoint(checkpoint, func(s *state.State) { s.CarriedAzqr = carried })
+	}
 
-			for i := 0; i < len(serviceScanners); i++ {
-				res := <-ch
-				for _, r := range res {
-					// check if the resource is excluded
-					if filters.Azqr.IsServiceExcluded(r.ResourceID()) {
-						continue
-					}
-					reportData.Azqr = append(reportData.Azqr, r)
-				}
+	// the Rego policies have no list of recommendations, so the violations are added to the recommendations
+	for _, d := range reportData.Azqr {
+		for id, r := range d.Recommendations {
+			t := strings.ToLower(d.Type)
+			if r.Source != scanners.SourcePolicy || reportData.Recommendations[t][id].RecommendationID != "" {
+				continue
+			}
+			if reportData.Recommendations[t] == nil {
+				reportData.Recommendations[t] = map[string]scanners.AprlRecommendation{}
 			}
+			recommendation := scanners.AzqrRecommendation{
+				RecommendationID: id,
+				ResourceType:     d.Type,
+				Category:         r.Category,
+				Recommendation:   r.Recommendation,
+				Impact:           r.Impact,
+				LearnMoreUrl:     r.LearnMoreUrl,
+			}
+			aprlRecommendation := recommendation.ToAzureAprlRecommendation()
+			aprlRecommendation.Source = scanners.SourcePolicy
+			reportData.Recommendations[t][id] = aprlRecommendation
 		}
-
-		// scan costs
-		costs := costScanner.Scan(params.Cost, config)
-		reportData.Cost.From = costs.From
-		reportData.Cost.To = costs.To
-		reportData.Cost.Items = append(reportData.Cost.Items, costs.Items...)
 	}
 
 	// get the count of resources per resource type
-	reportData.ResourceTypeCount = resourceScanner.GetCountPerResourceType(ctx, cred, subscriptions, reportData.Recommendations, filters)
+	if previous.Phases[state.PhaseResourceTypeCount] {
+		reportData.ResourceTypeCount = previous.ResourceTypeCount
+	} else {
+		resourceTypeCount, err := resourceScanner.GetCountPerResourceType(ctx, cred, subscriptions, reportData.Recommendations, filters, clientOptions)
+		if err != nil {
+			reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseResources, "", "", "Resource Type Count", err))
+		} else {
+			reportData.ResourceTypeCount = resourceTypeCount
+			saveCheckpoint(checkpoint, func(s *state.State) {
+				s.ResourceTypeCount = resourceTypeCount
+				s.Phases[state.PhaseResourceTypeCount] = true
+			})
+		}
+	}
 
 	// scan advisor
-	reportData.Advisor = append(reportData.Advisor, advisorScanner.Scan(ctx, params.Defender, cred, subscriptions, filters)...)
+	if previous.Phases[state.PhaseAdvisor] {
+		reportData.Advisor = append(reportData.Advisor, previous.Advisor...)
+	} else {
+		advisorResults, err := advisorScanner.Scan(ctx, params.Advisor, cred, subscriptions, filters, clientOptions)
+		if err != nil {
+			reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseAdvisor, "", "", "Advisor", err))
+		} else {
+			saveCheckpoint(checkpoint, func(s *state.State) {
+				s.Advisor = advisorResults
+				s.Phases[state.PhaseAdvisor] = true
+			})
+		}
+		reportData.Advisor = append(reportData.Advisor, advisorResults...)
+	}
 
 	// scan defender
-	reportData.Defender = append(reportData.Defender, defenderScanner.Scan(ctx, params.Defender, cred, subscriptions, filters)...)
+	if previous.Phases[state.PhaseDefender] {
+		reportData.Defender = append(reportData.Defender, previous.Defender...)
+	} else {
+		defenderResults, err := defenderScanner.Scan(ctx, params.Defender, cred, subscriptions, filters, clientOptions)
+		if err != nil {
+			reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseDefender, "", "", "Defender", err))
+		} else {
+			saveCheckpoint(checkpoint, func(s *state.State) {
+				s.Defender = defenderResults
+				s.Phases[state.PhaseDefender] = true
+			})
+		}
+		reportData.Defender = append(reportData.Defender, defenderResults...)
+	}
 
 	// get the defender recommendations
-	reportData.DefenderRecommendations = append(reportData.DefenderRecommendations, defenderScanner.GetRecommendations(ctx, params.Defender, cred, subscriptions, filters)...)
+	if previous.Phases[state.PhaseDefenderRecommendations] {
+		reportData.DefenderRecommendations = append(reportData.DefenderRecommendations, previous.DefenderRecommendations...)
+	} else {
+		defenderRecommendations, err := defenderScanner.GetRecommendations(ctx, params.Defender, cred, subscriptions, filters, clientOptions)
+		if err != nil {
+			reportData.Errors = append(reportData.Errors, scanners.NewScanError(scanners.PhaseDefender, "", "", "Defender Recommendations", err))
+		} else {
+			saveCheckpoint(checkpoint, func(s *state.State) {
+				s.DefenderRecommendations = defenderRecommendations
+				s.Phases[state.PhaseDefenderRecommendations] = true
+			})
+		}
+		reportData.DefenderRecommendations = append(reportData.DefenderRecommendations, defenderRecommendations...)
+	}
+
+	// move the findings of the active suppressions to the Suppressed table
+	reportData.ApplySuppressions(filters, time.Now())
+
+	// compose the SLAs of the resources of each workload
+	reportData.WorkloadSLAs = scanners.ComputeWorkloadSLAs(workloads, reportData.Resources, reportData.Azqr)
+
+	if len(reportData.Errors) > 0 {
+		log.Warn().Msgf("Scan completed with %d errors. The report contains partial results.", len(reportData.Errors))
+		return &reportData, nil
+	}
 
+	log.Info().Msg("Scan completed.")
+	return &reportData, nil
+}
+
+// Render renders the report data with the renderers enabled in the scan parameters
+func (sc Scanner) Render(data *renderers.ReportData, params *ScanParams) error {
 	// render excel report
-	excel.CreateExcelReport(&reportData)
+	if params.Xlsx {
+		if err := excel.CreateExcelReport(data); err != nil {
+			return err
+		}
+	}
 
 	// render json report
 	if params.Json {
-		json.CreateJsonReport(&reportData)
+		if err := json.CreateJsonReport(data); err != nil {
+			return err
+		}
 	}
 
 	// render csv reports
 	if params.Csv {
-		csv.CreateCsvReport(&reportData)
+		if err := csv.CreateCsvReport(data); err != nil {
+			return err
+		}
 	}
 
-	log.Info().Msg("Scan completed.")
+	// render html report
+	if params.Html {
+		if err := html.CreateHTMLReport(data); err != nil {
+			return err
+		}
+	}
+
+	// render sarif report
+	if params.Sarif {
+		if err := sarif.CreateSarifReport(data); err != nil {
+			return err
+		}
+	}
+
+	// render junit report
+	if params.Junit {
+		if err := junit.CreateJunitReport(data); err != nil {
+			return err
+		}
+	}
+
+	// render markdown summary
+	if params.Markdown {
+		if err := markdown.CreateMarkdownReport(data); err != nil {
+			return err
+		}
+	}
+
+	// render the remediation scripts
+	if params.Remediation {
+		if err := scripts.CreateRemediationScripts(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serviceScanResult - result of a single service scanner
+type serviceScanResult struct {
+	scanner string
+	results []scanners.AzqrServiceResult
+	err     error
+}
+
+// subscriptionScanResult - result of the scan of a single subscription
+type subscriptionScanResult struct {
+	azqr   []scanners.AzqrServiceResult
+	costs  *scanners.CostResult
+	errors []scanners.ScanError
+}
+
+// scanSubscription scans a single subscription with the AZQR service scanners and the cost scanner.
+// A failing scanner is reported as a scan error and does not stop the other scanners.
+func (sc Scanner) scanSubscription(config *scanners.ScannerConfig, params *ScanParams, rules *scanners.Rules, serviceScanners []scanners.IAzureScanner, filters *scanners.Filters, diagResults map[string]bool) subscriptionScanResult {
+	result := subscriptionScanResult{
+		azqr:   []scanners.AzqrServiceResult{},
+		errors: []scanners.ScanError{},
+	}
+
+	addError := func(phase, scanner string, err error) {
+		result.errors = append(result.errors, scanners.NewScanError(phase, config.SubscriptionID, config.SubscriptionName, scanner, err))
+	}
+
+	if params.UseAzqrRecommendations && len(serviceScanners) > 0 {
+		// scan private endpoints
+		peScanner := scanners.PrivateEndpointScanner{}
+		peResults, err := peScanner.Scan(config)
+		if err != nil {
+			addError(scanners.PhasePrivateEndpoints, "Private Endpoints", err)
+			peResults = map[string]bool{}
+		}
+
+		// scan public IPs
+		pipScanner := scanners.PublicIPScanner{}
+		pips, err := pipScanner.Scan(config)
+		if err != nil {
+			addError(scanners.PhasePublicIPs, "Public IPs", err)
+			pips = map[string]*armnetwork.PublicIPAddress{}
+		}
+
+		// initialize scan context
+		scanContext := scanners.ScanContext{
+			Ctx:                 config.Ctx,
+			Filters:             filters,
+			PrivateEndpoints:    peResults,
+			DiagnosticsSettings: diagResults,
+			PublicIPs:           pips,
+			Rules:               rules,
+		}
+
+		// run each service scanner with its own instance and scan context, since scanners keep state
+		results := make([]serviceScanResult, len(serviceScanners))
+		var wg sync.WaitGroup
+		for i, s := range serviceScanners {
+			s = scanners.NewScannerInstance(s)
+			name := strings.Join(s.ResourceTypes(), ", ")
+			err := s.Init(config)
+			if err != nil {
+				results[i] = serviceScanResult{scanner: name, err: fmt.Errorf("failed to initialize scanner: %w", err)}
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, s scanners.IAzureScanner, scanContext scanners.ScanContext) {
+				defer wg.Done()
+				res, err := sc.retry(3, 10*time.Millisecond, s, &scanContext)
+				results[i] = serviceScanResult{scanner: name, results: res, err: err}
+			}(i, s, scanContext)
+		}
+		wg.Wait()
+
+		for _, res := range results {
+			if res.err != nil {
+				addError(scanners.PhaseAzqr, res.scanner, res.err)
+				continue
+			}
+			for _, r := range res.results {
+				// check if the resource is excluded
+				if filters.Azqr.IsServiceExcluded(r.ResourceID()) {
+					continue
+				}
+				result.azqr = append(result.azqr, r)
+			}
+		}
+	}
+
+	// scan costs
+	costScanner := scanners.CostScanner{}
+	costs, err := costScanner.Scan(params.Cost, config)
+	if err != nil {
+		addError(scanners.PhaseCosts, "Cost Management", err)
+	}
+	result.costs = costs
+
+	return result
 }
 
 // retry retries the Azure scanner Scan, a number of times with an increasing delay between retries
 func (sc Scanner) retry(attempts int, sleep time.Duration, a scanners.IAzureScanner, scanContext *scanners.ScanContext) ([]scanners.AzqrServiceResult, error) {
 	var err error
 	for i := 0; ; i++ {
-		res, err := a.Scan(scanContext)
+		var res []scanners.AzqrServiceResult
+		res, err = a.Scan(scanContext)
 		if err == nil {
 			return res, nil
 		}
@@ -274,23 +756,6 @@ func (sc Scanner) retry(attempts int, sleep time.Duration, a scanners.IAzureScan
 	return nil, err
 }
 
-func (sc Scanner) newAzureCredential(forceAzureCliCredential bool) azcore.TokenCredential {
-	var cred azcore.TokenCredential
-	var err error
-	if !forceAzureCliCredential {
-		cred, err = azidentity.NewDefaultAzureCredential(nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to get Azure credentials")
-		}
-	} else {
-		cred, err = azidentity.NewAzureCLICredential(nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to get Azure CLI credentials")
-		}
-	}
-	return cred
-}
-
 func (sc Scanner) generateOutputFileName(outputName string) string {
 	outputFile := outputName
 	if outputFile == "" {
@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/cassette"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) Do(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+// scanners are created with NewScannerInstance, so the fakes cannot keep configuration in fields
+type workingScanner struct{}
+
+func (f *workingScanner) Init(config *scanners.ScannerConfig) error {
+	return nil
+}
+
+func (f *workingScanner) GetRecommendations() map[string]scanners.AzqrRecommendation {
+	return map[string]scanners.AzqrRecommendation{}
+}
+
+func (f *workingScanner) Scan(scanContext *scanners.ScanContext) ([]scanners.AzqrServiceResult, error) {
+	return []scanners.AzqrServiceResult{
+		{
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			ResourceGroup:  "rg",
+			ServiceName:    "fake",
+			Type:           "Microsoft.Fake/working",
+		},
+	}, nil
+}
+
+func (f *workingScanner) ResourceTypes() []string {
+	return []string{"Microsoft.Fake/working"}
+}
+
+type failingScanner struct {
+	workingScanner
+}
+
+func (f *failingScanner) Init(config *scanners.ScannerConfig) error {
+	return errors.New("init failed")
+}
+
+func (f *failingScanner) ResourceTypes() []string {
+	return []string{"Microsoft.Fake/failing"}
+}
+
+func TestScanSubscription_ContinuesOnErrors(t *testing.T) {
+	config := &scanners.ScannerConfig{
+		Ctx:              context.Background(),
+		SubscriptionID:   "00000000-0000-0000-0000-000000000000",
+		SubscriptionName: "test",
+		Cred:             cassette.Credential{},
+		ClientOptions: &arm.ClientOptions{
+			ClientOptions: policy.ClientOptions{
+				Transport: failingTransport{},
+				Retry:     policy.RetryOptions{MaxRetries: -1},
+			},
+		},
+	}
+
+	scanners.ScannerList["fake"] = []scanners.IAzureScanner{
+		&failingScanner{},
+		&workingScanner{},
+	}
+	defer delete(scanners.ScannerList, "fake")
+
+	filters, err := scanners.LoadFilters("", []string{"fake"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceScanners := filters.Azqr.Scanners
+
+	params := &ScanParams{UseAzqrRecommendations: true}
+	res := Scanner{}.scanSubscription(config, params, nil, serviceScanners, filters, map[string]bool{})
+
+	if len(res.azqr) != 1 || res.azqr[0].Type != "Microsoft.Fake/working" {
+		t.Fatalf("expected the results of the working scanner, got %v", res.azqr)
+	}
+
+	phases := []string{}
+	for _, e := range res.errors {
+		phases = append(phases, e.Phase)
+		if e.SubscriptionID != config.SubscriptionID {
+			t.Errorf("expected subscription %s, got %s", config.SubscriptionID, e.SubscriptionID)
+		}
+	}
+
+	expected := []string{scanners.PhasePrivateEndpoints, scanners.PhasePublicIPs, scanners.PhaseAzqr}
+	if strings.Join(phases, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected errors for %v, got %v", expected, phases)
+	}
+}
@@ -58,8 +58,7 @@ func (a *DataFactoryScanner) GetRecommendations() map[string]scanners.AzqrRecomm
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armdatafactory.Factory)
-				caf := strings.HasPrefix(*c.Name, "adf")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DataFactory/factories", "", *c.Name, "adf")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -71,7 +70,7 @@ func (a *DataFactoryScanner) GetRecommendations() map[string]scanners.AzqrRecomm
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armdatafactory.Factory)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DataFactory/factories", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -9,23 +9,28 @@ import (
 	"github.com/Azure/azqr/internal/graph"
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/rs/zerolog/log"
 )
 
 // AdvisorResult - Advisor result
 type AdvisorResult struct {
-	RecommendationID, SubscriptionID, SubscriptionName, Type, Name, ResourceID, Category, Impact, Description string
+	RecommendationID, SubscriptionID, SubscriptionName, Type, Name, ResourceID, Category, Impact, Description, TenantID string
 }
 
 // AdvisorScanner - Advisor scanner
 type AdvisorScanner struct{}
 
-func (s *AdvisorScanner) Scan(ctx context.Context, scan bool, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters) []AdvisorResult {
+// Scan - Returns the Azure Advisor recommendations for the given subscriptions
+func (s *AdvisorScanner) Scan(ctx context.Context, scan bool, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters, options *arm.ClientOptions) ([]AdvisorResult, error) {
 	LogResourceTypeScan("Advisor Recommendations")
 	resources := []AdvisorResult{}
 
 	if scan {
-		graphClient := graph.NewGraphQuery(cred)
+		graphClient, err := graph.NewGraphQuery(cred, options)
+		if err != nil {
+			return nil, err
+		}
 		query := `
 		AdvisorResources
 		| join kind=inner (
@@ -45,7 +50,10 @@ func (s *AdvisorScanner) Scan(ctx context.Context, scan bool, cred azcore.TokenC
 		for s := range subscriptions {
 			subs = append(subs, &s)
 		}
-		result := graphClient.Query(ctx, query, subs)
+		result, err := graphClient.Query(ctx, query, subs)
+		if err != nil {
+			return nil, err
+		}
 		resources = []AdvisorResult{}
 		if result.Data != nil {
 			for _, row := range result.Data {
@@ -74,5 +82,5 @@ func (s *AdvisorScanner) Scan(ctx context.Context, scan bool, cred azcore.TokenC
 			}
 		}
 	}
-	return resources
+	return resources, nil
 }
@@ -46,8 +46,7 @@ func (a *FrontDoorScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcdn.Profile)
-				caf := strings.HasPrefix(*c.Name, "afd")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Cdn/profiles", "", *c.Name, "afd")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -59,7 +58,7 @@ func (a *FrontDoorScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcdn.Profile)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Cdn/profiles", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
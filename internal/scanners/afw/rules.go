@@ -51,8 +51,7 @@ func (a *FirewallScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.AzureFirewall)
-				caf := strings.HasPrefix(*c.Name, "afw")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/azureFirewalls", "", *c.Name, "afw")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -64,7 +63,7 @@ func (a *FirewallScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.AzureFirewall)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/azureFirewalls", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
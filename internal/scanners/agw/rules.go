@@ -46,8 +46,7 @@ func (a *ApplicationGatewayScanner) GetRecommendations() map[string]scanners.Azq
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				g := target.(*armnetwork.ApplicationGateway)
-				caf := strings.HasPrefix(*g.Name, "agw")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/applicationGateways", "", *g.Name, "agw")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -59,7 +58,7 @@ func (a *ApplicationGatewayScanner) GetRecommendations() map[string]scanners.Azq
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.ApplicationGateway)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/applicationGateways", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -81,8 +81,7 @@ func (a *AKSScanner) GetRecommendations() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerservice.ManagedCluster)
-				caf := strings.HasPrefix(*c.Name, "aks")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.ContainerService/managedClusters", "", *c.Name, "aks")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -147,7 +146,7 @@ func (a *AKSScanner) GetRecommendations() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerservice.ManagedCluster)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.ContainerService/managedClusters", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -21,8 +21,7 @@ func (a *ManagedGrafanaScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armdashboard.ManagedGrafana)
-				caf := strings.HasPrefix(*c.Name, "amg")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Dashboard/managedGrafana", "", *c.Name, "amg")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -55,7 +54,7 @@ func (a *ManagedGrafanaScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armdashboard.ManagedGrafana)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Dashboard/managedGrafana", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -69,8 +69,7 @@ func (a *APIManagementScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armapimanagement.ServiceResource)
-				caf := strings.HasPrefix(*c.Name, "apim")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.ApiManagement/service", "", *c.Name, "apim")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -82,7 +81,7 @@ func (a *APIManagementScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armapimanagement.ServiceResource)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.ApiManagement/service", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -66,8 +66,7 @@ func (a *AppConfigurationScanner) GetRecommendations() map[string]scanners.AzqrR
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappconfiguration.ConfigurationStore)
-				caf := strings.HasPrefix(*c.Name, "appcs")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.AppConfiguration/configurationStores", "", *c.Name, "appcs")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -79,7 +78,7 @@ func (a *AppConfigurationScanner) GetRecommendations() map[string]scanners.AzqrR
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappconfiguration.ConfigurationStore)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.AppConfiguration/configurationStores", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -95,6 +94,11 @@ func (a *AppConfigurationScanner) GetRecommendations() map[string]scanners.AzqrR
 				return !localAuth, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-app-configuration/howto-disable-access-key-authentication?tabs=portal#disable-access-key-authentication",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az appconfig update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --disable-local-auth true",
+				Bicep:     "properties: { disableLocalAuth: true }",
+				Terraform: "local_auth_enabled = false",
+			},
 		},
 	}
 }
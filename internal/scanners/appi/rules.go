@@ -4,8 +4,6 @@
 package appi
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/applicationinsights/armapplicationinsights"
 )
@@ -33,8 +31,7 @@ func (a *AppInsightsScanner) GetRecommendations() map[string]scanners.AzqrRecomm
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armapplicationinsights.Component)
-				caf := strings.HasPrefix(*c.Name, "appi")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Insights/components", "", *c.Name, "appi")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -46,7 +43,7 @@ func (a *AppInsightsScanner) GetRecommendations() map[string]scanners.AzqrRecomm
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armapplicationinsights.Component)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Insights/components", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -52,8 +52,7 @@ func (a *AnalysisServicesScanner) GetRecommendations() map[string]scanners.AzqrR
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armanalysisservices.Server)
-				caf := strings.HasPrefix(*c.Name, "as")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.AnalysisServices/servers", "", *c.Name, "as")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -65,7 +64,7 @@ func (a *AnalysisServicesScanner) GetRecommendations() map[string]scanners.AzqrR
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armanalysisservices.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.AnalysisServices/servers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
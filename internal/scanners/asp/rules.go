@@ -65,8 +65,7 @@ func (a *AppServiceScanner) getPlanRules() map[string]scanners.AzqrRecommendatio
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Plan)
-				caf := strings.HasPrefix(*c.Name, "asp")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Web/serverfarms", "", *c.Name, "asp")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -78,7 +77,7 @@ func (a *AppServiceScanner) getPlanRules() map[string]scanners.AzqrRecommendatio
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Plan)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Web/serverfarms", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -121,8 +120,7 @@ func (a *AppServiceScanner) getAppRules() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				caf := strings.HasPrefix(*c.Name, "app")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Web/sites", "app", *c.Name, "app")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -147,7 +145,7 @@ func (a *AppServiceScanner) getAppRules() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Web/sites", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -289,8 +287,7 @@ func (a *AppServiceScanner) getFunctionRules() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				caf := strings.HasPrefix(*c.Name, "func")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Web/sites", "functionapp", *c.Name, "func")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -315,7 +312,7 @@ func (a *AppServiceScanner) getFunctionRules() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Web/sites", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -433,8 +430,7 @@ func (a *AppServiceScanner) getLogicRules() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				caf := strings.HasPrefix(*c.Name, "logic")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Web/sites", "workflowapp", *c.Name, "logic")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -459,7 +455,7 @@ func (a *AppServiceScanner) getLogicRules() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Web/sites", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
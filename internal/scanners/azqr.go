@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Azure/azqr/internal/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
@@ -31,12 +32,28 @@ type (
 
 	// ScanContext - Struct for Scanner Context
 	ScanContext struct {
+		// Ctx is the context of the scan, used to evaluate the policies
+		Ctx                   context.Context
 		Filters               *Filters
 		PrivateEndpoints      map[string]bool
 		DiagnosticsSettings   map[string]bool
 		PublicIPs             map[string]*armnetwork.PublicIPAddress
 		SiteConfig            *armappservice.WebAppsClientGetConfigurationResponse
 		BlobServiceProperties *armstorage.BlobServicesClientGetServicePropertiesResponse
+		// Rules are the rules loaded for the scan. The built-in rules are used when nil
+		Rules *Rules
+	}
+
+	// Rules - Rules loaded for a scan, evaluated with the Go rules of the scanners
+	Rules struct {
+		// Declarative are the built-in and custom declarative rules
+		Declarative DeclarativeRules
+		// Policies evaluates the Rego policies. Nil when no policies are loaded
+		Policies *policy.Engine
+		// Naming are the conventions of the naming policy. The CAF prefixes are expected when empty
+		Naming NamingConventions
+		// Tags is the tag policy. Nil when resources only need to have tags
+		Tags *TagPolicy
 	}
 
 	// IAzureScanner - Interface for all Azure Scanners
@@ -56,6 +73,9 @@ type (
 		Type             string
 		ServiceName      string
 		Recommendations  map[string]AzqrResult
+		TenantID         string
+		// ID is the resource id of services that are not provider resources, such as resource groups
+		ID string `json:",omitempty"`
 	}
 
 	AzqrRecommendation struct {
@@ -67,6 +87,11 @@ type (
 		RecommendationType RecommendationType
 		LearnMoreUrl       string
 		Eval               func(target interface{}, scanContext *ScanContext) (bool, string)
+		// EvalStatus is used instead of Eval when set. It reports rules that are not applicable to the resource
+		// or that failed to evaluate
+		EvalStatus func(target interface{}, scanContext *ScanContext) (RecommendationStatus, string)
+		// Remediation are the optional templates of the fix, rendered for the non compliant resources
+		Remediation *Remediation
 	}
 
 	AzqrResult struct {
@@ -77,8 +102,13 @@ type (
 		Impact             RecommendationImpact
 		RecommendationType RecommendationType
 		LearnMoreUrl       string
-		NotCompliant       bool
-		Result             string
+		// NotCompliant is true when Status is StatusNotCompliant
+		NotCompliant bool
+		Status       RecommendationStatus
+		Result       string
+		// Source of the rule, empty for the AZQR rules
+		Source      string
+		Remediation *Remediation
 	}
 
 	Resource struct {
@@ -92,6 +122,8 @@ type (
 		SkuTier        string
 		Kind           string
 		SLA            string
+		Tags           map[string]string
+		TenantID       string
 	}
 
 	ResourceTypeCount struct {
@@ -102,6 +134,7 @@ type (
 		Custom1         string  `json:"Custom1"`
 		Custom2         string  `json:"Custom2"`
 		Custom3         string  `json:"Custom3"`
+		TenantID        string  `json:"Tenant,omitempty"`
 	}
 
 	AprlRecommendation struct {
@@ -121,7 +154,9 @@ type (
 			Name string `yaml:"name"`
 			Url  string `yaml:"url"`
 		} `yaml:"learnMoreLink,flow"`
-		Source string
+		// Remediation are the optional templates of the fix. They are only used when AutomationAvailable is set
+		Remediation *Remediation `yaml:"remediation,omitempty"`
+		Source      string
 	}
 
 	AprlResult struct {
@@ -146,6 +181,8 @@ type (
 		Param5              string
 		AutomationAvailable string
 		Source              string
+		Remediation         *Remediation
+		TenantID            string
 	}
 
 	DefenderRecommendation struct {
@@ -161,6 +198,17 @@ type (
 		RemediationDescription string
 		AzPortalLink           string
 		ResourceId             string
+		TenantID               string
+	}
+
+	// ScanError - Error raised while scanning. The scan continues and the error is reported
+	ScanError struct {
+		Phase            string
+		SubscriptionID   string
+		SubscriptionName string
+		Scanner          string
+		Message          string
+		TenantID         string
 	}
 
 	RecommendationEngine struct{}
@@ -168,6 +216,7 @@ type (
 	RecommendationImpact   string
 	RecommendationCategory string
 	RecommendationType     string
+	RecommendationStatus   string
 )
 
 const (
@@ -187,8 +236,38 @@ const (
 
 	TypeRecommendation RecommendationType = ""
 	TypeSLA            RecommendationType = "SLA"
+
+	StatusCompliant     RecommendationStatus = "Compliant"
+	StatusNotCompliant  RecommendationStatus = "NotCompliant"
+	StatusNotApplicable RecommendationStatus = "NotApplicable"
+	StatusError         RecommendationStatus = "Error"
+
+	PhaseSubscriptions    = "Subscriptions"
+	PhaseAprl             = "APRL"
+	PhaseResources        = "Resources"
+	PhaseDiagnostics      = "Diagnostic Settings"
+	PhasePrivateEndpoints = "Private Endpoints"
+	PhasePublicIPs        = "Public IPs"
+	PhaseAzqr             = "AZQR"
+	PhaseCosts            = "Costs"
+	PhaseAdvisor          = "Advisor"
+	PhaseDefender         = "Defender"
+	PhaseTenant           = "Tenant"
+	PhaseRule             = "Rule"
 )
 
+// NewScanError - Creates a ScanError for the given phase, subscription and scanner
+func NewScanError(phase, subscriptionID, subscriptionName, scanner string, err error) ScanError {
+	log.Error().Err(err).Str("scanner", scanner).Msgf("%s scan failed. The scan will continue", phase)
+	return ScanError{
+		Phase:            phase,
+		SubscriptionID:   subscriptionID,
+		SubscriptionName: subscriptionName,
+		Scanner:          scanner,
+		Message:          err.Error(),
+	}
+}
+
 func (r *AzqrRecommendation) ToAzureAprlRecommendation() AprlRecommendation {
 	return AprlRecommendation{
 		RecommendationID:    r.RecommendationID,
@@ -214,29 +293,103 @@ func (r *AzqrRecommendation) ToAzureAprlRecommendation() AprlRecommendation {
 func (e *RecommendationEngine) EvaluateRecommendations(rules map[string]AzqrRecommendation, target interface{}, scanContext *ScanContext) map[string]AzqrResult {
 	results := map[string]AzqrResult{}
 
+	// declarative rules of the resource type, evaluated against its ARM JSON.
+	// They replace the Go rules with the same id. The resource is only converted when
+	// the resource types of the Go rules have declarative rules or when policies are loaded
+	declarativeRules, policies := scanContext.declarativeRules(), scanContext.policies()
+	declarative := map[string]AzqrRecommendation{}
+	var document map[string]interface{}
+	if declarativeRules.hasRulesFor(rules) || policies != nil {
+		var err error
+		if document, err = toDocument(target); err != nil {
+			log.Warn().Err(err).Msg("Failed to convert resource to JSON. Declarative rules and policies are skipped")
+		} else if t, ok := document["type"].(string); ok {
+			declarative = declarativeRules.Recommendations(t)
+		}
+	}
+
 	for k, rule := range rules {
+		if _, ok := declarative[k]; ok {
+			continue
+		}
 		results[k] = e.evaluateRecommendation(rule, target, scanContext)
 	}
 
+	for k, rule := range declarative {
+		results[k] = e.evaluateRecommendation(rule, document, scanContext)
+	}
+
+	// violations of the Rego policies
+	if document != nil && policies != nil {
+		for k, r := range evaluatePolicies(policies, document, scanContext) {
+			results[k] = r
+		}
+	}
+
 	return results
 }
 
-func (e *RecommendationEngine) evaluateRecommendation(rule AzqrRecommendation, target interface{}, scanContext *ScanContext) AzqrResult {
-	broken, result := rule.Eval(target, scanContext)
+// declarativeRules returns the declarative rules of the scan. Nil when the scan has no rules: only the Go rules are evaluated
+func (c *ScanContext) declarativeRules() DeclarativeRules {
+	if c == nil || c.Rules == nil {
+		return nil
+	}
+	return c.Rules.Declarative
+}
+
+// policies returns the Rego policies of the scan. Nil when no policies are loaded
+func (c *ScanContext) policies() *policy.Engine {
+	if c == nil || c.Rules == nil {
+		return nil
+	}
+	return c.Rules.Policies
+}
+
+// Evaluate returns the status of a resource for the rule, with EvalStatus when set or with Eval
+func (r *AzqrRecommendation) Evaluate(target interface{}, scanContext *ScanContext) (RecommendationStatus, string) {
+	if r.EvalStatus != nil {
+		return r.EvalStatus(target, scanContext)
+	}
 
-	return AzqrResult{
+	broken, result := r.Eval(target, scanContext)
+	if broken {
+		return StatusNotCompliant, result
+	}
+	return StatusCompliant, result
+}
+
+// evaluateRecommendation evaluates a rule against a resource.
+// A rule that panics, e.g. dereferencing a nil property, is reported with StatusError
+func (e *RecommendationEngine) evaluateRecommendation(rule AzqrRecommendation, target interface{}, scanContext *ScanContext) (result AzqrResult) {
+	result = AzqrResult{
 		RecommendationID:   rule.RecommendationID,
 		Category:           rule.Category,
 		Recommendation:     rule.Recommendation,
 		RecommendationType: rule.RecommendationType,
 		Impact:             rule.Impact,
 		LearnMoreUrl:       rule.LearnMoreUrl,
-		Result:             result,
-		NotCompliant:       broken,
+		Remediation:        rule.Remediation,
 	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warn().Msgf("Failed to evaluate recommendation %s: %v", rule.RecommendationID, r)
+			result.Status = StatusError
+			result.NotCompliant = false
+			result.Result = fmt.Sprint(r)
+		}
+	}()
+
+	result.Status, result.Result = rule.Evaluate(target, scanContext)
+	result.NotCompliant = result.Status == StatusNotCompliant
+	return result
 }
 
+// ResourceID returns the lower case resource id, built from the type and name of the service when ID is not set
 func (r *AzqrServiceResult) ResourceID() string {
+	if r.ID != "" {
+		return strings.ToLower(r.ID)
+	}
 	return strings.ToLower(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s/%s", r.SubscriptionID, r.ResourceGroup, r.Type, r.ServiceName))
 }
 
@@ -311,9 +464,12 @@ func GetResourceGroupIDFromResourceID(resourceID string) string {
 	return strings.Join(parts[:5], "/")
 }
 
-// GetResourceNameFromResourceID - Get Resource Type from Resource ID
+// GetResourceNameFromResourceID - Get Resource Type from Resource ID. Resource group ids have the resource group type
 func GetResourceTypeFromResourceID(resourceID string) string {
 	parts := strings.Split(resourceID, "/")
+	if len(parts) == 5 && strings.EqualFold(parts[3], "resourceGroups") {
+		return "Microsoft.Resources/resourceGroups"
+	}
 	if len(parts) < 8 {
 		return ""
 	}
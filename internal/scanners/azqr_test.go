@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+func TestRecommendationEngine_Status(t *testing.T) {
+	rules := map[string]AzqrRecommendation{
+		"compliant": {
+			RecommendationID: "compliant",
+			Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+				return false, ""
+			},
+		},
+		"broken": {
+			RecommendationID: "broken",
+			Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+				return true, ""
+			},
+		},
+		"panic": {
+			RecommendationID: "panic",
+			Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+				c := target.(*armstorage.Account)
+				return !*c.Properties.EnableHTTPSTrafficOnly, ""
+			},
+		},
+		"not-applicable": {
+			RecommendationID: "not-applicable",
+			EvalStatus: func(target interface{}, scanContext *ScanContext) (RecommendationStatus, string) {
+				return StatusNotApplicable, ""
+			},
+		},
+	}
+
+	engine := RecommendationEngine{}
+	results := engine.EvaluateRecommendations(rules, &armstorage.Account{}, &ScanContext{})
+
+	expected := map[string]RecommendationStatus{
+		"compliant":      StatusCompliant,
+		"broken":         StatusNotCompliant,
+		"panic":          StatusError,
+		"not-applicable": StatusNotApplicable,
+	}
+	for id, status := range expected {
+		r := results[id]
+		if r.Status != status || r.NotCompliant != (status == StatusNotCompliant) {
+			t.Errorf("%s: expected status %s, got %+v", id, status, r)
+		}
+	}
+	if results["panic"].Result == "" {
+		t.Error("expected the panic to be reported in the result")
+	}
+}
+
+// writeFile writes the content of a YAML fixture to a temporary file and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
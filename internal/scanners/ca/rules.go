@@ -4,8 +4,6 @@
 package ca
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
 )
@@ -33,8 +31,7 @@ func (a *ContainerAppsScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappcontainers.ContainerApp)
-				caf := strings.HasPrefix(*c.Name, "ca")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.App/containerApps", "", *c.Name, "ca")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -46,7 +43,7 @@ func (a *ContainerAppsScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappcontainers.ContainerApp)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.App/containerApps", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
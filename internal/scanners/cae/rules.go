@@ -59,8 +59,7 @@ func (a *ContainerAppsEnvironmentScanner) GetRecommendations() map[string]scanne
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappcontainers.ManagedEnvironment)
-				caf := strings.HasPrefix(*c.Name, "cae")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.App/managedenvironments", "", *c.Name, "cae")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -72,7 +71,7 @@ func (a *ContainerAppsEnvironmentScanner) GetRecommendations() map[string]scanne
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappcontainers.ManagedEnvironment)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.App/managedenvironments", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
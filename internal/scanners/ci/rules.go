@@ -4,8 +4,6 @@
 package ci
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerinstance/armcontainerinstance"
 )
@@ -61,8 +59,7 @@ func (a *ContainerInstanceScanner) GetRecommendations() map[string]scanners.Azqr
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerinstance.ContainerGroup)
-				caf := strings.HasPrefix(*c.Name, "ci")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.ContainerInstance/containerGroups", "", *c.Name, "ci")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -74,7 +71,7 @@ func (a *ContainerInstanceScanner) GetRecommendations() map[string]scanners.Azqr
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerinstance.ContainerGroup)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.ContainerInstance/containerGroups", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
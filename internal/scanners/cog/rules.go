@@ -59,36 +59,36 @@ func (a *CognitiveScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcognitiveservices.Account)
+				prefix := "cog"
 				switch strings.ToLower(*c.Kind) {
 				case "openai":
-					return !strings.HasPrefix(*c.Name, "oai"), ""
+					prefix = "oai"
 				case "computervision":
-					return !strings.HasPrefix(*c.Name, "cv"), ""
+					prefix = "cv"
 				case "contentmoderator":
-					return !strings.HasPrefix(*c.Name, "cm"), ""
+					prefix = "cm"
 				case "contentsafety":
-					return !strings.HasPrefix(*c.Name, "cs"), ""
+					prefix = "cs"
 				case "customvision.prediction":
-					return !strings.HasPrefix(*c.Name, "cstv"), ""
+					prefix = "cstv"
 				case "customvision.training":
-					return !strings.HasPrefix(*c.Name, "cstvt"), ""
+					prefix = "cstvt"
 				case "formrecognizer":
-					return !strings.HasPrefix(*c.Name, "di"), ""
+					prefix = "di"
 				case "face":
-					return !strings.HasPrefix(*c.Name, "face"), ""
+					prefix = "face"
 				case "healthinsights":
-					return !strings.HasPrefix(*c.Name, "hi"), ""
+					prefix = "hi"
 				case "immersivereader":
-					return !strings.HasPrefix(*c.Name, "ir"), ""
+					prefix = "ir"
 				case "textanalytics":
-					return !strings.HasPrefix(*c.Name, "lang"), ""
+					prefix = "lang"
 				case "speechservices":
-					return !strings.HasPrefix(*c.Name, "spch"), ""
+					prefix = "spch"
 				case "texttranslation":
-					return !strings.HasPrefix(*c.Name, "trsl"), ""
-				default:
-					return !strings.HasPrefix(*c.Name, "cog"), ""
+					prefix = "trsl"
 				}
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.CognitiveServices/accounts", *c.Kind, *c.Name, prefix)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -100,7 +100,7 @@ func (a *CognitiveScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcognitiveservices.Account)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.CognitiveServices/accounts", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
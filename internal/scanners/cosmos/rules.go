@@ -103,8 +103,7 @@ func (a *CosmosDBScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcosmos.DatabaseAccountGetResults)
-				caf := strings.HasPrefix(*c.Name, "cosmos")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DocumentDB/databaseAccounts", "", *c.Name, "cosmos")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -116,7 +115,7 @@ func (a *CosmosDBScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcosmos.DatabaseAccountGetResults)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DocumentDB/databaseAccounts", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
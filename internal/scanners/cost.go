@@ -8,7 +8,6 @@ import (
 
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/costmanagement/armcostmanagement"
-	"github.com/rs/zerolog/log"
 )
 
 // CostResult - Cost result
@@ -19,7 +18,7 @@ type CostResult struct {
 
 // CostResultItem - Cost result ite,
 type CostResultItem struct {
-	SubscriptionID, SubscriptionName, ServiceName, Value, Currency string
+	SubscriptionID, SubscriptionName, ServiceName, Value, Currency, TenantID string
 }
 
 // CostScanner - Cost scanner
@@ -95,22 +94,26 @@ func (s *CostScanner) QueryCosts() (*CostResult, error) {
 	return &result, nil
 }
 
-func (s *CostScanner) Scan(scan bool, config *ScannerConfig) *CostResult {
+// Scan - Returns the costs of the subscription if scan is true
+func (s *CostScanner) Scan(scan bool, config *ScannerConfig) (*CostResult, error) {
 	costResult := &CostResult{
 		Items: []*CostResultItem{},
 	}
 	if scan {
 		err := s.Init(config)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to initialize Cost Scanner")
+			return nil, fmt.Errorf("failed to initialize Cost Scanner: %w", err)
 		}
 		costs, err := s.QueryCosts()
-		if err != nil && !ShouldSkipError(err) {
-			log.Fatal().Err(err).Msg("Failed to query costs")
+		if err != nil {
+			if ShouldSkipError(err) {
+				return costResult, nil
+			}
+			return nil, fmt.Errorf("failed to query costs: %w", err)
 		}
 		costResult.From = costs.From
 		costResult.To = costs.To
 		costResult.Items = append(costResult.Items, costs.Items...)
 	}
-	return costResult
+	return costResult, nil
 }
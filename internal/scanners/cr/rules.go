@@ -59,8 +59,7 @@ func (a *ContainerRegistryScanner) GetRecommendations() map[string]scanners.Azqr
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerregistry.Registry)
-				caf := strings.HasPrefix(*c.Name, "cr")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.ContainerRegistry/registries", "", *c.Name, "cr")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -76,6 +75,11 @@ func (a *ContainerRegistryScanner) GetRecommendations() map[string]scanners.Azqr
 				return admin, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/azure/container-registry/container-registry-authentication-managed-identity",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az acr update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --admin-enabled false",
+				Bicep:     "properties: { adminUserEnabled: false }",
+				Terraform: "admin_enabled = false",
+			},
 		},
 		"cr-009": {
 			RecommendationID: "cr-009",
@@ -85,7 +89,7 @@ func (a *ContainerRegistryScanner) GetRecommendations() map[string]scanners.Azqr
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerregistry.Registry)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.ContainerRegistry/registries", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -60,8 +60,7 @@ func (a *DatabricksScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armdatabricks.Workspace)
-				caf := strings.HasPrefix(*c.Name, "dbw")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Databricks/workspaces", "", *c.Name, "dbw")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -82,8 +82,7 @@ func (a *DataExplorerScanner) GetRecommendations() map[string]scanners.AzqrRecom
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armkusto.Cluster)
-				caf := strings.HasPrefix(*c.Name, "dec")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Kusto/clusters", "", *c.Name, "dec")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -95,7 +94,7 @@ func (a *DataExplorerScanner) GetRecommendations() map[string]scanners.AzqrRecom
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armkusto.Cluster)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Kusto/clusters", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -0,0 +1,188 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azqr/internal/rules"
+	"github.com/rs/zerolog/log"
+)
+
+// DeclarativeRules - Declarative rules by lower case resource type and id
+type DeclarativeRules map[string]map[string]AzqrRecommendation
+
+// LoadDeclarativeRules loads the built-in declarative rules and the rules of the given directories.
+// A rule with the id of a built-in rule replaces it
+func LoadDeclarativeRules(dirs []string) (DeclarativeRules, error) {
+	definitions, err := rules.Builtin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load built-in rules: %w", err)
+	}
+
+	for _, dir := range dirs {
+		r, err := rules.LoadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Msgf("Loaded %d rules from %s", len(r), dir)
+		definitions = append(definitions, r...)
+	}
+
+	loaded := DeclarativeRules{}
+	for _, d := range definitions {
+		recommendation, err := newDeclarativeRecommendation(d)
+		if err != nil {
+			return nil, err
+		}
+
+		t := strings.ToLower(d.ResourceType)
+		if loaded[t] == nil {
+			loaded[t] = map[string]AzqrRecommendation{}
+		}
+		loaded[t][d.RecommendationID] = recommendation
+	}
+	return loaded, nil
+}
+
+// Recommendations returns the declarative rules of a resource type
+func (r DeclarativeRules) Recommendations(resourceType string) map[string]AzqrRecommendation {
+	return r[strings.ToLower(resourceType)]
+}
+
+// hasRulesFor returns true when the resource types of the Go rules have declarative rules
+func (r DeclarativeRules) hasRulesFor(recommendations map[string]AzqrRecommendation) bool {
+	for _, rec := range recommendations {
+		if len(r.Recommendations(rec.ResourceType)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ScannerRecommendations returns the rules of a scanner: its Go rules and the declarative rules of its resource types
+func ScannerRecommendations(s IAzureScanner, declarative DeclarativeRules) map[string]AzqrRecommendation {
+	recommendations := map[string]AzqrRecommendation{}
+	for k, r := range s.GetRecommendations() {
+		recommendations[k] = r
+	}
+	for _, t := range s.ResourceTypes() {
+		for k, r := range declarative.Recommendations(t) {
+			recommendations[k] = r
+		}
+	}
+	return recommendations
+}
+
+// newDeclarativeRecommendation creates the recommendation of a declarative rule
+func newDeclarativeRecommendation(r *rules.Rule) (AzqrRecommendation, error) {
+	category, err := parseCategory(r.Category)
+	if err != nil {
+		return AzqrRecommendation{}, fmt.Errorf("rule %s in %s: %w", r.RecommendationID, r.Source, err)
+	}
+	impact, err := parseImpact(r.Impact)
+	if err != nil {
+		return AzqrRecommendation{}, fmt.Errorf("rule %s in %s: %w", r.RecommendationID, r.Source, err)
+	}
+
+	return AzqrRecommendation{
+		RecommendationID: r.RecommendationID,
+		ResourceType:     r.ResourceType,
+		Category:         category,
+		Recommendation:   r.Recommendation,
+		Impact:           impact,
+		LearnMoreUrl:     r.LearnMoreUrl,
+		EvalStatus: func(target interface{}, scanContext *ScanContext) (RecommendationStatus, string) {
+			document, err := toDocument(target)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Failed to evaluate rule %s", r.RecommendationID)
+				return StatusError, err.Error()
+			}
+
+			vars := ruleVariables(document, scanContext)
+			applies, err := r.Applicable(vars)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Failed to evaluate rule %s", r.RecommendationID)
+				return StatusError, err.Error()
+			}
+			if !applies {
+				return StatusNotApplicable, ""
+			}
+
+			broken, result, err := r.Evaluate(vars)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Failed to evaluate rule %s", r.RecommendationID)
+				return StatusError, err.Error()
+			}
+			if broken {
+				return StatusNotCompliant, result
+			}
+			return StatusCompliant, result
+		},
+	}, nil
+}
+
+// ruleVariables returns the variables of the rule expressions for a resource
+func ruleVariables(document map[string]interface{}, scanContext *ScanContext) map[string]interface{} {
+	id, _ := document["id"].(string)
+
+	diagnosticSettings, privateEndpoint := false, false
+	if scanContext != nil {
+		diagnosticSettings = scanContext.DiagnosticsSettings[strings.ToLower(id)]
+		privateEndpoint = scanContext.PrivateEndpoints[id] || scanContext.PrivateEndpoints[strings.ToLower(id)]
+	}
+
+	return map[string]interface{}{
+		rules.VarResource:           document,
+		rules.VarDiagnosticSettings: diagnosticSettings,
+		rules.VarPrivateEndpoint:    privateEndpoint,
+	}
+}
+
+// toDocument converts an ARM SDK model to its ARM JSON
+func toDocument(target interface{}) (map[string]interface{}, error) {
+	if document, ok := target.(map[string]interface{}); ok {
+		return document, nil
+	}
+
+	content, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	document := map[string]interface{}{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func parseCategory(category string) (RecommendationCategory, error) {
+	for _, c := range []RecommendationCategory{
+		CategoryBusinessContinuity,
+		CategoryDisasterRecovery,
+		CategoryGovernance,
+		CategoryHighAvailability,
+		CategoryMonitoringAndAlerting,
+		CategoryOtherBestPractices,
+		CategoryScalability,
+		CategorySecurity,
+		CategoryServiceUpgradeAndRetirement,
+	} {
+		if strings.EqualFold(string(c), category) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown category %s", category)
+}
+
+func parseImpact(impact string) (RecommendationImpact, error) {
+	for _, i := range []RecommendationImpact{ImpactHigh, ImpactMedium, ImpactLow} {
+		if strings.EqualFold(string(i), impact) {
+			return i, nil
+		}
+	}
+	return "", fmt.Errorf("unknown impact %s", impact)
+}
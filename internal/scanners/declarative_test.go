@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+func TestRecommendationEngine_DeclarativeRules(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+- id: test-001
+  resourceType: Microsoft.Storage/storageAccounts
+  category: Security
+  impact: Low
+  recommendation: Go rule replaced by a declarative rule
+  expression: "false"
+- id: test-002
+  resourceType: Microsoft.Storage/storageAccounts
+  category: MonitoringAndAlerting
+  impact: Low
+  recommendation: Storage Account should have diagnostic settings
+  expression: "!diagnosticSettings"
+  result: resource.name
+- id: test-003
+  resourceType: Microsoft.Storage/storageAccounts
+  category: Security
+  impact: Low
+  recommendation: Premium Storage Account rule
+  applies: resource.kind == 'BlockBlobStorage'
+  expression: "true"
+`
+	if err := os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	declarative, err := LoadDeclarativeRules([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanContext := &ScanContext{DiagnosticsSettings: map[string]bool{}, Rules: &Rules{Declarative: declarative}}
+
+	goRules := map[string]AzqrRecommendation{
+		"test-001": {
+			RecommendationID: "test-001",
+			ResourceType:     "Microsoft.Storage/storageAccounts",
+			Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+				return true, ""
+			},
+		},
+	}
+
+	id := "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"
+	account := &armstorage.Account{
+		ID:         to.Ptr(id),
+		Name:       to.Ptr("st"),
+		Type:       to.Ptr("Microsoft.Storage/storageAccounts"),
+		Properties: &armstorage.AccountProperties{},
+	}
+
+	engine := RecommendationEngine{}
+	results := engine.EvaluateRecommendations(goRules, account, scanContext)
+
+	if results["test-001"].NotCompliant {
+		t.Error("expected the declarative rule to replace the Go rule")
+	}
+	if r := results["test-002"]; !r.NotCompliant || r.Result != "st" || r.Category != CategoryMonitoringAndAlerting {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r := results["test-003"]; r.Status != StatusNotApplicable || r.NotCompliant {
+		t.Errorf("expected a not applicable rule, got %+v", r)
+	}
+	if r := results["st-012"]; !r.NotCompliant {
+		t.Error("expected the built-in rule st-012 to be evaluated")
+	}
+
+	// resources of types without declarative rules are not converted
+	other := map[string]AzqrRecommendation{
+		"test-004": {
+			RecommendationID: "test-004",
+			ResourceType:     "Microsoft.Test/tests",
+			Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+				return true, ""
+			},
+		},
+	}
+	results = engine.EvaluateRecommendations(other, func() {}, scanContext)
+	if len(results) != 1 || !results["test-004"].NotCompliant {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestRecommendationEngine_NoRules(t *testing.T) {
+	goRules := map[string]AzqrRecommendation{
+		"test-001": {
+			RecommendationID: "test-001",
+			ResourceType:     "Microsoft.Storage/storageAccounts",
+			Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+				return true, ""
+			},
+		},
+	}
+
+	engine := RecommendationEngine{}
+	results := engine.EvaluateRecommendations(goRules, &armstorage.Account{}, &ScanContext{})
+	if len(results) != 1 || !results["test-001"].NotCompliant {
+		t.Errorf("expected only the Go rules without the rules of the scan, got %+v", results)
+	}
+}
+
+func TestLoadDeclarativeRules_InvalidCategory(t *testing.T) {
+	dir := t.TempDir()
+	content := "- {id: test-001, resourceType: t, category: Unknown, impact: Low, recommendation: r, expression: 'true'}"
+	if err := os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDeclarativeRules([]string{dir}); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+}
@@ -5,28 +5,36 @@ package scanners
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/Azure/azqr/internal/clouds"
 	"github.com/Azure/azqr/internal/graph"
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/rs/zerolog/log"
 )
 
 // DefenderResult - Defender result
 type DefenderResult struct {
-	SubscriptionID, SubscriptionName, Name, Tier string
+	SubscriptionID, SubscriptionName, Name, Tier, TenantID string
 }
 
 // DefenderScanner - Defender scanner
-type DefenderScanner struct{}
+type DefenderScanner struct {
+	// Cloud is the cloud of the portal links. Empty uses the public cloud
+	Cloud clouds.Cloud
+}
 
-func (s *DefenderScanner) Scan(ctx context.Context, scan bool, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters) []DefenderResult {
+// Scan - Returns the Microsoft Defender for Cloud plans status for the given subscriptions
+func (s *DefenderScanner) Scan(ctx context.Context, scan bool, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters, options *arm.ClientOptions) ([]DefenderResult, error) {
 	LogResourceTypeScan("Defender Status")
 	resources := []DefenderResult{}
 
 	if scan {
-		graphClient := graph.NewGraphQuery(cred)
+		graphClient, err := graph.NewGraphQuery(cred, options)
+		if err != nil {
+			return nil, err
+		}
 		query := `
 		SecurityResources
 		| join kind=inner (
@@ -42,7 +50,10 @@ func (s *DefenderScanner) Scan(ctx context.Context, scan bool, cred azcore.Token
 		for s := range subscriptions {
 			subs = append(subs, &s)
 		}
-		result := graphClient.Query(ctx, query, subs)
+		result, err := graphClient.Query(ctx, query, subs)
+		if err != nil {
+			return nil, err
+		}
 		resources = []DefenderResult{}
 		if result.Data != nil {
 			for _, row := range result.Data {
@@ -61,15 +72,19 @@ func (s *DefenderScanner) Scan(ctx context.Context, scan bool, cred azcore.Token
 			}
 		}
 	}
-	return resources
+	return resources, nil
 }
 
-func (s *DefenderScanner) GetRecommendations(ctx context.Context, scan bool, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters) []DefenderRecommendation {
+// GetRecommendations - Returns the unhealthy Microsoft Defender for Cloud assessments for the given subscriptions
+func (s *DefenderScanner) GetRecommendations(ctx context.Context, scan bool, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters, options *arm.ClientOptions) ([]DefenderRecommendation, error) {
 	LogResourceTypeScan("Defender Recommendations")
 	resources := []DefenderRecommendation{}
 
 	if scan {
-		graphClient := graph.NewGraphQuery(cred)
+		graphClient, err := graph.NewGraphQuery(cred, options)
+		if err != nil {
+			return nil, err
+		}
 		query := `
 		SecurityResources
 		| where type == 'microsoft.security/assessments'
@@ -107,8 +122,15 @@ func (s *DefenderScanner) GetRecommendations(ctx context.Context, scan bool, cre
 		for s := range subscriptions {
 			subs = append(subs, &s)
 		}
-		result := graphClient.Query(ctx, query, subs)
+		result, err := graphClient.Query(ctx, query, subs)
+		if err != nil {
+			return nil, err
+		}
 		resources = []DefenderRecommendation{}
+		portal := s.Cloud
+		if portal.Portal == "" {
+			portal = clouds.AzurePublic
+		}
 		if result.Data != nil {
 			for _, row := range result.Data {
 				m := row.(map[string]interface{})
@@ -128,11 +150,11 @@ func (s *DefenderScanner) GetRecommendations(ctx context.Context, scan bool, cre
 					RecommendationName:     to.String(m["RecommendationName"]),
 					ActionDescription:      to.String(m["ActionDescription"]),
 					RemediationDescription: to.String(m["RemediationDescription"]),
-					AzPortalLink:           fmt.Sprintf("https://%s", to.String(m["AzPortalLink"])),
+					AzPortalLink:           portal.PortalLink(to.String(m["AzPortalLink"])),
 					ResourceId:             to.String(m["ResourceId"]),
 				})
 			}
 		}
 	}
-	return resources
+	return resources, nil
 }
@@ -10,7 +10,6 @@ import (
 	"net/http"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -54,7 +53,7 @@ func (d *DiagnosticSettingsScanner) ListResourcesWithDiagnosticSettings(resource
 
 	log.Debug().Msgf("Number of diagnostic setting batches: %d", batches)
 	jobs := make(chan []*string, batches)
-	ch := make(chan map[string]bool, batches)
+	ch := make(chan diagnosticSettingsBatchResult, batches)
 	var wg sync.WaitGroup
 
 	// Start workers
@@ -65,45 +64,57 @@ func (d *DiagnosticSettingsScanner) ListResourcesWithDiagnosticSettings(resource
 	}
 	wg.Add(batches)
 
-	// Split resources into batches of 20 items.
+	// Split resources into batches of 20 items. Throttling is handled by the graph.RateLimiter policy of the client
 	batchSize := 20
-	batchCount := 0
 	for i := 0; i < len(resources); i += batchSize {
 		j := i + batchSize
 		if j > len(resources) {
 			j = len(resources)
 		}
 		jobs <- resources[i:j]
-
-		batchCount++
-		if batchCount == numWorkers {
-			log.Debug().Msgf("all %d workers are running. Sleeping for 4 seconds to avoid throttling", numWorkers)
-			batchCount = 0
-			// there are more batches to process
-			// Staggering queries to avoid throttling. Max 15 queries each 5 seconds.
-			// https://learn.microsoft.com/en-us/azure/governance/resource-graph/concepts/guidance-for-throttled-requests#staggering-queries
-			time.Sleep(4 * time.Second)
-		}
 	}
 
 	// Wait for all workers to finish
 	close(jobs)
 	wg.Wait()
 
+	// failed batches are reported, but the results of the other batches are still returned
+	var batchErr error
+	failed := 0
 	for i := 0; i < batches; i++ {
-		for k, v := range <-ch {
+		r := <-ch
+		if r.err != nil {
+			if batchErr == nil {
+				batchErr = r.err
+			}
+			failed++
+			continue
+		}
+		for k, v := range r.resources {
 			res[k] = v
 		}
 	}
 
+	if batchErr != nil {
+		return res, fmt.Errorf("failed to get diagnostic settings for %d of %d batches: %w", failed, batches, batchErr)
+	}
+
 	return res, nil
 }
 
-func (d *DiagnosticSettingsScanner) worker(jobs <-chan []*string, results chan<- map[string]bool, wg *sync.WaitGroup) {
+// diagnosticSettingsBatchResult - result of a single diagnostic settings batch request
+type diagnosticSettingsBatchResult struct {
+	resources map[string]bool
+	err       error
+}
+
+func (d *DiagnosticSettingsScanner) worker(jobs <-chan []*string, results chan<- diagnosticSettingsBatchResult, wg *sync.WaitGroup) {
 	for ids := range jobs {
 		resp, err := d.restCall(d.ctx, ids)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to get diagnostic settings")
+			results <- diagnosticSettingsBatchResult{err: err}
+			wg.Done()
+			continue
 		}
 		asyncRes := map[string]bool{}
 		for _, response := range resp.Responses {
@@ -112,7 +123,7 @@ func (d *DiagnosticSettingsScanner) worker(jobs <-chan []*string, results chan<-
 				asyncRes[id] = true
 			}
 		}
-		results <- asyncRes
+		results <- diagnosticSettingsBatchResult{resources: asyncRes}
 		wg.Done()
 	}
 }
@@ -190,14 +201,15 @@ type (
 	}
 )
 
-func (d *DiagnosticSettingsScanner) Scan(resources []*string) map[string]bool {
+// Scan - Returns the resources with diagnostic settings
+func (d *DiagnosticSettingsScanner) Scan(resources []*string) (map[string]bool, error) {
 	diagResults, err := d.ListResourcesWithDiagnosticSettings(resources)
 	if err != nil {
 		if ShouldSkipError(err) {
 			diagResults = map[string]bool{}
 		} else {
-			log.Fatal().Err(err).Msg("Failed to list resources with Diagnostic Settings")
+			return diagResults, fmt.Errorf("failed to list resources with Diagnostic Settings: %w", err)
 		}
 	}
-	return diagResults
+	return diagResults, nil
 }
@@ -59,8 +59,7 @@ func (a *EventGridScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armeventgrid.Domain)
-				caf := strings.HasPrefix(*c.Name, "evgd")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.EventGrid/domains", "", *c.Name, "evgd")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -72,7 +71,7 @@ func (a *EventGridScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armeventgrid.Domain)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.EventGrid/domains", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -65,8 +65,7 @@ func (a *EventHubScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armeventhub.EHNamespace)
-				caf := strings.HasPrefix(*c.Name, "evh")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.EventHub/namespaces", "", *c.Name, "evh")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -78,7 +77,7 @@ func (a *EventHubScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armeventhub.EHNamespace)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.EventHub/namespaces", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -94,6 +93,11 @@ func (a *EventHubScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 				return !localAuth, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/event-hubs/authorize-access-event-hubs#shared-access-signatures",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az eventhubs namespace update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --disable-local-auth true",
+				Bicep:     "properties: { disableLocalAuth: true }",
+				Terraform: "local_authentication_enabled = false",
+			},
 		},
 	}
 }
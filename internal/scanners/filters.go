@@ -3,13 +3,22 @@
 package scanners
 
 import (
+	"fmt"
+	"maps"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 )
 
+// regexPrefix is the prefix of the filter entries that are regular expressions
+const regexPrefix = "regex:"
+
 type (
 	Filters struct {
 		Azqr *AzqrFilter `yaml:"azqr"`
@@ -18,6 +27,7 @@ type (
 	AzqrFilter struct {
 		Include          *IncludeFilter `yaml:"include"`
 		Exclude          *ExcludeFilter `yaml:"exclude"`
+		Suppressions     []Suppression  `yaml:"suppressions"`
 		iSubscriptions   map[string]bool
 		iResourceGroups  map[string]bool
 		iResourceTypes   map[string]bool
@@ -25,7 +35,23 @@ type (
 		xResourceGroups  map[string]bool
 		xServices        map[string]bool
 		xRecommendations map[string]bool
-		Scanners         []IAzureScanner
+		// iTagged are the resources matching the tag filters. Nil until they are loaded
+		iTagged map[string]bool
+		// glob and regex patterns of the include and exclude filters
+		iSubscriptionPatterns   []*filterPattern
+		iResourceGroupPatterns  []*filterPattern
+		xSubscriptionPatterns   []*filterPattern
+		xResourceGroupPatterns  []*filterPattern
+		xServicePatterns        []*filterPattern
+		xRecommendationPatterns []*filterPattern
+		Scanners                []IAzureScanner
+	}
+
+	// filterPattern - Glob or regex pattern of a filter entry
+	filterPattern struct {
+		entry   string
+		re      *regexp.Regexp
+		matched atomic.Bool
 	}
 
 	// ExcludeFilter - Struct for ExcludeFilter
@@ -34,6 +60,8 @@ type (
 		ResourceGroups  []string `yaml:"resourceGroups,flow"`
 		Services        []string `yaml:"services,flow"`
 		Recommendations []string `yaml:"recommendations,flow"`
+		// Tags excludes the resources with any of the tag values
+		Tags map[string][]string `yaml:"tags"`
 	}
 
 	// IncludeFilter - Struct for IncludeFilter
@@ -41,6 +69,8 @@ type (
 		Subscriptions  []string `yaml:"subscriptions,flow"`
 		ResourceGroups []string `yaml:"resourceGroups,flow"`
 		ResourceTypes  []string `yaml:"resourceTypes,flow"`
+		// Tags includes only the resources with one of the values of every tag
+		Tags map[string][]string `yaml:"tags"`
 	}
 )
 
@@ -61,44 +91,129 @@ func (e *AzqrFilter) AddResourceGroup(resourceGroupID string) {
 }
 
 func (e *AzqrFilter) IsSubscriptionExcluded(subscriptionID string) bool {
-	_, ok := e.iSubscriptions[strings.ToLower(subscriptionID)]
-	if ok {
+	// Check if the subscription is included
+	if matches(subscriptionID, e.iSubscriptions, e.iSubscriptionPatterns) {
 		return false
 	}
 
-	_, ok = e.xSubscriptions[strings.ToLower(subscriptionID)]
-	return ok
+	// If not included, but there are included subscriptions, then exclude it
+	if len(e.iSubscriptions) > 0 || len(e.iSubscriptionPatterns) > 0 {
+		return true
+	}
+
+	return matches(subscriptionID, e.xSubscriptions, e.xSubscriptionPatterns)
 }
 
 func (e *AzqrFilter) IsServiceExcluded(resourceID string) bool {
 	t := GetResourceTypeFromResourceID(resourceID)
 	if _, included := e.iResourceTypes[strings.ToLower(t)]; included {
-		sID := GetSubscriptionFromResourceID(resourceID)
-		excluded := e.IsSubscriptionExcluded(sID)
+		return e.IsResourceExcluded(resourceID)
+	} else {
+		log.Debug().Msgf("Service type is excluded: %s", t)
+		return true
+	}
+}
+
+// IsResourceExcluded returns true when the subscription, resource group, id or tags of a resource are excluded.
+// Unlike IsServiceExcluded, the resource type does not need a selected scanner
+func (e *AzqrFilter) IsResourceExcluded(resourceID string) bool {
+	sID := GetSubscriptionFromResourceID(resourceID)
+	excluded := e.IsSubscriptionExcluded(sID)
+
+	if !excluded {
+		rgID := GetResourceGroupIDFromResourceID(resourceID)
+		excluded = e.isResourceGroupExcluded(rgID)
 
 		if !excluded {
-			rgID := GetResourceGroupIDFromResourceID(resourceID)
-			excluded = e.isResourceGroupExcluded(rgID)
+			excluded = matches(resourceID, e.xServices, e.xServicePatterns)
+		}
+
+		if !excluded && e.iTagged != nil {
+			excluded = !e.iTagged[strings.ToLower(resourceID)]
+		}
+	}
+
+	if excluded {
+		log.Debug().Msgf("Service is excluded: %s", resourceID)
+	}
+
+	return excluded
+}
 
-			if !excluded {
-				_, excluded = e.xServices[strings.ToLower(resourceID)]
+func (e *AzqrFilter) IsRecommendationExcluded(recommendationID string) bool {
+	return matches(recommendationID, e.xRecommendations, e.xRecommendationPatterns)
+}
+
+// UnmatchedPatterns returns the glob and regex patterns of the include and exclude filters that never matched
+func (e *AzqrFilter) UnmatchedPatterns() []string {
+	unmatched := []string{}
+	for _, patterns := range e.patterns() {
+		for _, p := range patterns {
+			if !p.matched.Load() {
+				unmatched = append(unmatched, p.entry)
 			}
 		}
+	}
+	return unmatched
+}
 
-		if excluded {
-			log.Debug().Msgf("Service is excluded: %s", resourceID)
+// AddMatchedPatterns marks the patterns matched by a clone of the filters, e.g. the filters of a tenant, as matched
+func (e *AzqrFilter) AddMatchedPatterns(clone *AzqrFilter) {
+	patterns, cloned := e.patterns(), clone.patterns()
+	for i := range patterns {
+		for j, p := range patterns[i] {
+			if j < len(cloned[i]) && cloned[i][j].matched.Load() {
+				p.matched.Store(true)
+			}
 		}
+	}
+}
 
-		return excluded
-	} else {
-		log.Debug().Msgf("Service type is excluded: %s", t)
-		return true
+// patterns returns the glob and regex patterns of the filters, in the same order for the clones
+func (e *AzqrFilter) patterns() [][]*filterPattern {
+	return [][]*filterPattern{
+		e.iSubscriptionPatterns, e.iResourceGroupPatterns,
+		e.xSubscriptionPatterns, e.xResourceGroupPatterns, e.xServicePatterns, e.xRecommendationPatterns,
 	}
 }
 
-func (e *AzqrFilter) IsRecommendationExcluded(recommendationID string) bool {
-	_, ok := e.xRecommendations[strings.ToLower(recommendationID)]
-	return ok
+// HasTagFilters returns true when resources are included or excluded by their tags
+func (e *AzqrFilter) HasTagFilters() bool {
+	return len(e.Include.Tags) > 0 || len(e.Exclude.Tags) > 0
+}
+
+// tagsQuery returns the resources, resource groups and subscriptions with their tags, keyed by lower case tag names.
+// Resources without tags keep a single row with no tags
+const tagsQuery = "resources | union resourcecontainers | project id, tags | mv-expand tags" +
+	" | extend tagKey = tostring(bag_keys(tags)[0])" +
+	" | extend tag = iff(isempty(tagKey), dynamic({}), pack(tolower(tagKey), tostring(tags[tagKey])))" +
+	" | summarize tags = make_bag(tag) by id"
+
+// TagQuery returns the Resource Graph query of the resources, resource groups and subscriptions matching the tag filters.
+// Tag names are case insensitive
+func (e *AzqrFilter) TagQuery() string {
+	conditions := []string{}
+	for _, k := range sortedKeys(e.Include.Tags) {
+		conditions = append(conditions, tagCondition(k, e.Include.Tags[k]))
+	}
+
+	excluded := []string{}
+	for _, k := range sortedKeys(e.Exclude.Tags) {
+		excluded = append(excluded, tagCondition(k, e.Exclude.Tags[k]))
+	}
+	if len(excluded) > 0 {
+		conditions = append(conditions, fmt.Sprintf("not(%s)", strings.Join(excluded, " or ")))
+	}
+
+	return fmt.Sprintf("%s | where %s | project id", tagsQuery, strings.Join(conditions, " and "))
+}
+
+// SetTaggedResources sets the resources matching the tag filters. Other resources are excluded
+func (e *AzqrFilter) SetTaggedResources(resourceIDs []string) {
+	e.iTagged = make(map[string]bool, len(resourceIDs))
+	for _, id := range resourceIDs {
+		e.iTagged[strings.ToLower(id)] = true
+	}
 }
 
 func (e *AzqrFilter) IsResourceTypeExcluded(resourceType string) bool {
@@ -106,7 +221,42 @@ func (e *AzqrFilter) IsResourceTypeExcluded(resourceType string) bool {
 	return !ok
 }
 
-func LoadFilters(filterFile string, scannerKeys []string) *Filters {
+// Clone returns a copy of the filters, so adding subscriptions, resource groups or excluded resources
+// to the copy does not change the original filters
+func (f *Filters) Clone() *Filters {
+	if f == nil || f.Azqr == nil {
+		return f
+	}
+
+	e := *f.Azqr
+	if e.Include != nil {
+		include := *e.Include
+		include.Subscriptions = append([]string{}, include.Subscriptions...)
+		include.ResourceGroups = append([]string{}, include.ResourceGroups...)
+		include.ResourceTypes = append([]string{}, include.ResourceTypes...)
+		e.Include = &include
+	}
+	if e.Exclude != nil {
+		exclude := *e.Exclude
+		e.Exclude = &exclude
+	}
+	e.Suppressions = append([]Suppression{}, e.Suppressions...)
+	e.Scanners = append([]IAzureScanner{}, e.Scanners...)
+	e.iSubscriptions = maps.Clone(e.iSubscriptions)
+	e.iResourceGroups = maps.Clone(e.iResourceGroups)
+	e.iResourceTypes = maps.Clone(e.iResourceTypes)
+	e.iTagged = maps.Clone(e.iTagged)
+	e.iSubscriptionPatterns = clonePatterns(e.iSubscriptionPatterns)
+	e.iResourceGroupPatterns = clonePatterns(e.iResourceGroupPatterns)
+	e.xSubscriptionPatterns = clonePatterns(e.xSubscriptionPatterns)
+	e.xResourceGroupPatterns = clonePatterns(e.xResourceGroupPatterns)
+	e.xServicePatterns = clonePatterns(e.xServicePatterns)
+	e.xRecommendationPatterns = clonePatterns(e.xRecommendationPatterns)
+	return &Filters{Azqr: &e}
+}
+
+// LoadFilters loads the filters file (if any) and selects the scanners for the given keys
+func LoadFilters(filterFile string, scannerKeys []string) (*Filters, error) {
 	filters := &Filters{
 		Azqr: &AzqrFilter{
 			Include: &IncludeFilter{
@@ -127,33 +277,50 @@ func LoadFilters(filterFile string, scannerKeys []string) *Filters {
 	if filterFile != "" {
 		data, err := os.ReadFile(filterFile)
 		if err != nil {
-			log.Fatal().Err(err).Msgf("failed reading data from file: %s", filterFile)
+			return nil, fmt.Errorf("failed reading data from file: %s: %w", filterFile, err)
 		}
 
 		err = yaml.Unmarshal([]byte(data), &filters)
 		if err != nil {
-			log.Fatal().Err(err).Msgf("failed parsing yaml from file: %s", filterFile)
+			return nil, fmt.Errorf("failed parsing yaml from file: %s: %w", filterFile, err)
+		}
+	}
+
+	for _, tags := range []map[string][]string{filters.Azqr.Include.Tags, filters.Azqr.Exclude.Tags} {
+		for _, k := range sortedKeys(tags) {
+			if len(tags[k]) == 0 {
+				return nil, fmt.Errorf("invalid tag filter in file: %s: tag %s has no values", filterFile, k)
+			}
 		}
 	}
 
-	filters.Azqr.xResourceGroups = make(map[string]bool)
-	for _, id := range filters.Azqr.Exclude.ResourceGroups {
-		filters.Azqr.xResourceGroups[strings.ToLower(id)] = true
+	var err error
+	if filters.Azqr.iResourceGroups, filters.Azqr.iResourceGroupPatterns, err = compileEntries(filters.Azqr.Include.ResourceGroups); err != nil {
+		return nil, fmt.Errorf("invalid resource group filter in file: %s: %w", filterFile, err)
 	}
 
-	filters.Azqr.xSubscriptions = make(map[string]bool)
-	for _, id := range filters.Azqr.Exclude.Subscriptions {
-		filters.Azqr.xSubscriptions[strings.ToLower(id)] = true
+	if filters.Azqr.iSubscriptions, filters.Azqr.iSubscriptionPatterns, err = compileEntries(filters.Azqr.Include.Subscriptions); err != nil {
+		return nil, fmt.Errorf("invalid subscription filter in file: %s: %w", filterFile, err)
 	}
 
-	filters.Azqr.xServices = make(map[string]bool)
-	for _, id := range filters.Azqr.Exclude.Services {
-		filters.Azqr.xServices[strings.ToLower(id)] = true
+	if filters.Azqr.xResourceGroups, filters.Azqr.xResourceGroupPatterns, err = compileEntries(filters.Azqr.Exclude.ResourceGroups); err != nil {
+		return nil, fmt.Errorf("invalid resource group filter in file: %s: %w", filterFile, err)
 	}
 
-	filters.Azqr.xRecommendations = make(map[string]bool)
-	for _, id := range filters.Azqr.Exclude.Recommendations {
-		filters.Azqr.xRecommendations[strings.ToLower(id)] = true
+	if filters.Azqr.xSubscriptions, filters.Azqr.xSubscriptionPatterns, err = compileEntries(filters.Azqr.Exclude.Subscriptions); err != nil {
+		return nil, fmt.Errorf("invalid subscription filter in file: %s: %w", filterFile, err)
+	}
+
+	if filters.Azqr.xServices, filters.Azqr.xServicePatterns, err = compileEntries(filters.Azqr.Exclude.Services); err != nil {
+		return nil, fmt.Errorf("invalid service filter in file: %s: %w", filterFile, err)
+	}
+
+	if filters.Azqr.xRecommendations, filters.Azqr.xRecommendationPatterns, err = compileEntries(filters.Azqr.Exclude.Recommendations); err != nil {
+		return nil, fmt.Errorf("invalid recommendation filter in file: %s: %w", filterFile, err)
+	}
+
+	if err := filters.Azqr.compileSuppressions(time.Now()); err != nil {
+		return nil, fmt.Errorf("invalid suppression in file: %s: %w", filterFile, err)
 	}
 
 	s := []IAzureScanner{}
@@ -179,22 +346,93 @@ func LoadFilters(filterFile string, scannerKeys []string) *Filters {
 		}
 	}
 
-	return filters
+	return filters, nil
+}
+
+// tagCondition returns the KQL condition of a tag with one of the values
+func tagCondition(key string, values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, kqlString(v))
+	}
+	return fmt.Sprintf("tostring(tags[%s]) in~ (%s)", kqlString(strings.ToLower(key)), strings.Join(quoted, ", "))
+}
+
+// kqlString quotes a KQL string literal
+func kqlString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func (e *AzqrFilter) isResourceGroupExcluded(resourceGroupID string) bool {
 	// Check if the resource group is included
-	_, ok := e.iResourceGroups[strings.ToLower(resourceGroupID)]
-	if ok {
+	if matches(resourceGroupID, e.iResourceGroups, e.iResourceGroupPatterns) {
 		return false
 	}
 
 	// If not included, but there are included resource groups, then exclude it
-	if len(e.iResourceGroups) > 0 {
+	if len(e.iResourceGroups) > 0 || len(e.iResourceGroupPatterns) > 0 {
 		return true
 	}
 
 	// Check if the resource group is excluded
-	_, ok = e.xResourceGroups[strings.ToLower(resourceGroupID)]
-	return ok
+	return matches(resourceGroupID, e.xResourceGroups, e.xResourceGroupPatterns)
+}
+
+// compileEntries splits the entries of a filter in lower case ids and patterns.
+// Entries with * or ? are globs, where * and ? do not match /. Entries starting with regex: are regular expressions.
+// Both are case insensitive and must match the whole id
+func compileEntries(entries []string) (map[string]bool, []*filterPattern, error) {
+	ids := map[string]bool{}
+	patterns := []*filterPattern{}
+	for _, entry := range entries {
+		var expr string
+		switch {
+		case strings.HasPrefix(entry, regexPrefix):
+			expr = strings.TrimPrefix(entry, regexPrefix)
+		case strings.ContainsAny(entry, "*?"):
+			expr = strings.NewReplacer(`\*`, `[^/]*`, `\?`, `[^/]`).Replace(regexp.QuoteMeta(entry))
+		default:
+			ids[strings.ToLower(entry)] = true
+			continue
+		}
+
+		re, err := regexp.Compile("(?i)^(?:" + expr + ")$")
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern %s: %w", entry, err)
+		}
+		patterns = append(patterns, &filterPattern{entry: entry, re: re})
+	}
+	return ids, patterns, nil
+}
+
+// clonePatterns copies the patterns, so the copies are not matched yet
+func clonePatterns(patterns []*filterPattern) []*filterPattern {
+	result := make([]*filterPattern, 0, len(patterns))
+	for _, p := range patterns {
+		result = append(result, &filterPattern{entry: p.entry, re: p.re})
+	}
+	return result
+}
+
+// matches returns true when the id is one of the ids or matches one of the patterns
+func matches(id string, ids map[string]bool, patterns []*filterPattern) bool {
+	if ids[strings.ToLower(id)] {
+		return true
+	}
+	for _, p := range patterns {
+		if p.re.MatchString(id) {
+			p.matched.Store(true)
+			return true
+		}
+	}
+	return false
 }
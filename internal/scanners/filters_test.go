@@ -0,0 +1,202 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAzqrFilter_Tags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "filters.yaml")
+	content := `
+azqr:
+  include:
+    tags:
+      environment: [prod, "o'neil"]
+  exclude:
+    tags:
+      Azqr-Ignore: ["true"]
+`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := LoadFilters(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filters.Azqr.HasTagFilters() {
+		t.Fatal("expected tag filters")
+	}
+
+	expected := tagsQuery + ` | where tostring(tags['environment']) in~ ('prod', 'o\'neil') and not(tostring(tags['azqr-ignore']) in~ ('true')) | project id`
+	if q := filters.Azqr.TagQuery(); q != expected {
+		t.Errorf("unexpected query %s", q)
+	}
+
+	id := "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"
+	filters.Azqr.iResourceTypes["microsoft.storage/storageaccounts"] = true
+	if filters.Azqr.IsServiceExcluded(id) {
+		t.Error("expected the resource to be included")
+	}
+	filters.Azqr.SetTaggedResources([]string{"/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/other"})
+	if !filters.Azqr.IsServiceExcluded(id) {
+		t.Error("expected the resource to be excluded by its tags")
+	}
+	filters.Azqr.SetTaggedResources([]string{strings.ToUpper(id)})
+	if filters.Azqr.IsServiceExcluded(id) {
+		t.Error("expected the resource matching the tag filters to be included")
+	}
+
+	for _, content := range []string{"azqr: {include: {tags: {environment: []}}}", "azqr: {exclude: {tags: {azqr-ignore: []}}}"} {
+		if _, err := LoadFilters(writeFile(t, "filters.yaml", content), nil); err == nil {
+			t.Errorf("expected an error for the empty tag values of %s", content)
+		}
+	}
+}
+
+func TestAzqrFilter_Patterns(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "filters.yaml")
+	content := `
+azqr:
+  exclude:
+    subscriptions: ["regex:0000-.*"]
+    resourceGroups: ["/subscriptions/*/resourceGroups/rg-sandbox-*"]
+    services: ["/subscriptions/*/resourceGroups/*/providers/Microsoft.Storage/storageAccounts/st?"]
+    recommendations: ["*-006", st-001, "regex:aprl-(a|b)"]
+`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := LoadFilters(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filters.Azqr.iResourceTypes["microsoft.storage/storageaccounts"] = true
+
+	excluded := map[string]bool{
+		"/subscriptions/1111/resourceGroups/RG-Sandbox-01/providers/Microsoft.Storage/storageAccounts/account": true,
+		"/subscriptions/1111/resourceGroups/rg-prod/providers/Microsoft.Storage/storageAccounts/st1":           true,
+		"/subscriptions/1111/resourceGroups/rg-prod/providers/Microsoft.Storage/storageAccounts/st12":          false,
+		"/subscriptions/1111/resourceGroups/rg-prod/providers/Microsoft.Storage/storageAccounts/account":       false,
+	}
+	for id, expected := range excluded {
+		if filters.Azqr.IsServiceExcluded(id) != expected {
+			t.Errorf("expected excluded %t for %s", expected, id)
+		}
+	}
+
+	recommendations := map[string]bool{"st-006": true, "ST-001": true, "st-0061": false, "aprl-a": true, "aprl-c": false}
+	for id, expected := range recommendations {
+		if filters.Azqr.IsRecommendationExcluded(id) != expected {
+			t.Errorf("expected excluded %t for %s", expected, id)
+		}
+	}
+
+	if unmatched := filters.Azqr.UnmatchedPatterns(); len(unmatched) != 1 || unmatched[0] != "regex:0000-.*" {
+		t.Errorf("unexpected unmatched patterns %v", unmatched)
+	}
+
+	if err := os.WriteFile(file, []byte("azqr: {exclude: {recommendations: ['regex:(']}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFilters(file, nil); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+}
+
+func TestAzqrFilter_IncludePatterns(t *testing.T) {
+	content := `
+azqr:
+  include:
+    subscriptions: ["regex:1111-.*", "2222-*"]
+    resourceGroups: ["/subscriptions/*/resourceGroups/rg-prod-*"]
+`
+	filters, err := LoadFilters(writeFile(t, "filters.yaml", content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filters.Azqr.iResourceTypes["microsoft.storage/storageaccounts"] = true
+
+	subscriptions := map[string]bool{"1111-0000": false, "0000-1111": true}
+	for id, expected := range subscriptions {
+		if filters.Azqr.IsSubscriptionExcluded(id) != expected {
+			t.Errorf("expected excluded %t for subscription %s", expected, id)
+		}
+	}
+
+	excluded := map[string]bool{
+		"/subscriptions/1111-0000/resourceGroups/RG-PROD-01/providers/Microsoft.Storage/storageAccounts/st": false,
+		"/subscriptions/1111-0000/resourceGroups/rg-dev-01/providers/Microsoft.Storage/storageAccounts/st":  true,
+	}
+	for id, expected := range excluded {
+		if filters.Azqr.IsServiceExcluded(id) != expected {
+			t.Errorf("expected excluded %t for %s", expected, id)
+		}
+	}
+
+	// the patterns matched by the filters of a tenant are matched for the scan
+	clone := filters.Clone()
+	clone.Azqr.IsSubscriptionExcluded("2222-0000")
+	if unmatched := filters.Azqr.UnmatchedPatterns(); len(unmatched) != 1 || unmatched[0] != "2222-*" {
+		t.Errorf("unexpected unmatched patterns %v", unmatched)
+	}
+	filters.Azqr.AddMatchedPatterns(clone.Azqr)
+	if unmatched := filters.Azqr.UnmatchedPatterns(); len(unmatched) != 0 {
+		t.Errorf("unexpected unmatched patterns %v", unmatched)
+	}
+}
+
+func TestFilters_Clone(t *testing.T) {
+	filters, err := LoadFilters("", []string{"st"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := filters.Clone()
+	clone.Azqr.AddSubscription("0000")
+	clone.Azqr.AddResourceGroup("/subscriptions/0000/resourceGroups/rg")
+	clone.Azqr.SetTaggedResources([]string{"/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"})
+
+	if len(filters.Azqr.Include.Subscriptions) != 0 || len(filters.Azqr.iSubscriptions) != 0 {
+		t.Error("expected the subscriptions of the original filters to be unchanged")
+	}
+	if len(filters.Azqr.Include.ResourceGroups) != 0 || len(filters.Azqr.iResourceGroups) != 0 {
+		t.Error("expected the resource groups of the original filters to be unchanged")
+	}
+	if filters.Azqr.iTagged != nil {
+		t.Error("expected the tagged resources of the original filters to be unchanged")
+	}
+	if len(clone.Azqr.Scanners) != len(filters.Azqr.Scanners) {
+		t.Errorf("expected %d scanners, got %d", len(filters.Azqr.Scanners), len(clone.Azqr.Scanners))
+	}
+}
+
+func TestAzqrFilter_ResourceGroupExcluded(t *testing.T) {
+	filters, err := LoadFilters("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filters.Azqr.iResourceTypes["microsoft.resources/resourcegroups"] = true
+
+	result := AzqrServiceResult{
+		SubscriptionID: "0000",
+		ResourceGroup:  "rg",
+		Type:           "Microsoft.Resources/resourceGroups",
+		ServiceName:    "rg",
+		ID:             "/subscriptions/0000/resourceGroups/rg",
+	}
+	if filters.Azqr.IsServiceExcluded(result.ResourceID()) {
+		t.Error("expected the resource group to be included")
+	}
+
+	filters.Azqr.AddResourceGroup("/subscriptions/0000/resourceGroups/other")
+	if !filters.Azqr.IsServiceExcluded(result.ResourceID()) {
+		t.Error("expected the resource group outside the included resource groups to be excluded")
+	}
+}
@@ -4,8 +4,6 @@
 package it
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/virtualmachineimagebuilder/armvirtualmachineimagebuilder/v2"
 )
@@ -21,8 +19,7 @@ func (a *ImageTemplateScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armvirtualmachineimagebuilder.ImageTemplate)
-				caf := strings.HasPrefix(*c.Name, "it")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.VirtualMachineImages/imageTemplates", "", *c.Name, "it")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -34,7 +31,7 @@ func (a *ImageTemplateScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armvirtualmachineimagebuilder.ImageTemplate)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.VirtualMachineImages/imageTemplates", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -46,8 +46,7 @@ func (a *KeyVaultScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armkeyvault.Vault)
-				caf := strings.HasPrefix(*c.Name, "kv")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.KeyVault/vaults", "", *c.Name, "kv")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -59,7 +58,7 @@ func (a *KeyVaultScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armkeyvault.Vault)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.KeyVault/vaults", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -4,6 +4,7 @@
 package lb
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/Azure/azqr/internal/scanners"
@@ -68,8 +69,37 @@ func (a *LoadBalancerScanner) GetRecommendations() map[string]scanners.AzqrRecom
 					}
 				}
 
-				caf := (strings.HasPrefix(*c.Name, "lbi") && hasPrivateIP) || (strings.HasPrefix(*c.Name, "lbe") && hasPublicIP)
-				return !caf, ""
+				// without a naming convention, the CAF prefix of either frontend is accepted
+				if !scanners.HasNamingConvention(scanContext, "Microsoft.Network/loadBalancers", "Public") &&
+					!scanners.HasNamingConvention(scanContext, "Microsoft.Network/loadBalancers", "Internal") {
+					expected := []string{}
+					if hasPrivateIP {
+						if strings.HasPrefix(*c.Name, "lbi") {
+							return false, ""
+						}
+						expected = append(expected, "lbi*")
+					}
+					if hasPublicIP {
+						if strings.HasPrefix(*c.Name, "lbe") {
+							return false, ""
+						}
+						expected = append(expected, "lbe*")
+					}
+					if len(expected) == 0 {
+						return true, ""
+					}
+					return true, fmt.Sprintf("Expected: %s", strings.Join(expected, " or "))
+				}
+
+				// internal and public load balancers have their own conventions
+				switch {
+				case hasPublicIP:
+					return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/loadBalancers", "Public", *c.Name, "lbe")
+				case hasPrivateIP:
+					return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/loadBalancers", "Internal", *c.Name, "lbi")
+				default:
+					return true, ""
+				}
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -81,7 +111,7 @@ func (a *LoadBalancerScanner) GetRecommendations() map[string]scanners.AzqrRecom
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.LoadBalancer)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/loadBalancers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
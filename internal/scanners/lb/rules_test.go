@@ -107,6 +107,85 @@ func TestLoadBalancerScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "LoadBalancerScanner CAF Internal Load Balancer with public frontend",
+			fields: fields{
+				rule: "lb-006",
+				target: &armnetwork.LoadBalancer{
+					Name: to.Ptr("lbi"),
+					Properties: &armnetwork.LoadBalancerPropertiesFormat{
+						FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+							{
+								Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+									PrivateIPAddress: to.Ptr("10.0.0.1"),
+								},
+							},
+							{
+								Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+									PublicIPAddress: &armnetwork.PublicIPAddress{},
+								},
+							},
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "LoadBalancerScanner CAF Load Balancer without prefix",
+			fields: fields{
+				rule: "lb-006",
+				target: &armnetwork.LoadBalancer{
+					Name: to.Ptr("lb-test"),
+					Properties: &armnetwork.LoadBalancerPropertiesFormat{
+						FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+							{
+								Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+									PrivateIPAddress: to.Ptr("10.0.0.1"),
+								},
+							},
+							{
+								Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+									PublicIPAddress: &armnetwork.PublicIPAddress{},
+								},
+							},
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+			},
+			want: want{
+				broken: true,
+				result: "Expected: lbi* or lbe*",
+			},
+		},
+		{
+			name: "LoadBalancerScanner CAF Internal Load Balancer with public prefix",
+			fields: fields{
+				rule: "lb-006",
+				target: &armnetwork.LoadBalancer{
+					Name: to.Ptr("lbe-test"),
+					Properties: &armnetwork.LoadBalancerPropertiesFormat{
+						FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+							{
+								Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+									PrivateIPAddress: to.Ptr("10.0.0.1"),
+								},
+							},
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+			},
+			want: want{
+				broken: true,
+				result: "Expected: lbi*",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -4,8 +4,6 @@
 package log
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights/v2"
 )
@@ -33,8 +31,7 @@ func (a *LogAnalyticsScanner) GetRecommendations() map[string]scanners.AzqrRecom
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armoperationalinsights.Workspace)
-				caf := strings.HasPrefix(*c.Name, "log")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.OperationalInsights/workspaces", "", *c.Name, "log")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -46,7 +43,7 @@ func (a *LogAnalyticsScanner) GetRecommendations() map[string]scanners.AzqrRecom
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armoperationalinsights.Workspace)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.OperationalInsights/workspaces", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
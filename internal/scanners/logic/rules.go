@@ -78,8 +78,7 @@ func (a *LogicAppScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armlogic.Workflow)
 
-				caf := strings.HasPrefix(*c.Name, "logic")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Logic/workflows", "", *c.Name, "logic")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -91,7 +90,7 @@ func (a *LogicAppScanner) GetRecommendations() map[string]scanners.AzqrRecommend
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armlogic.Workflow)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Logic/workflows", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
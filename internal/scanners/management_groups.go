@@ -2,6 +2,7 @@ package scanners
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -13,10 +14,11 @@ import (
 
 type ManagementGroupsScanner struct{}
 
-func (sc ManagementGroupsScanner) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, groupID string, filters *Filters, options *arm.ClientOptions) map[string]string {
+// ListSubscriptions returns the enabled subscriptions under the management group. Key is subscription ID, value is subscription name
+func (sc ManagementGroupsScanner) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, groupID string, filters *Filters, options *arm.ClientOptions) (map[string]string, error) {
 	client, err := armmanagementgroups.NewClientFactory(cred, options)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create management groups client")
+		return nil, fmt.Errorf("failed to create management groups client: %w", err)
 	}
 
 	resultPager := client.NewManagementGroupSubscriptionsClient().NewGetSubscriptionsUnderManagementGroupPager(groupID, nil)
@@ -25,7 +27,7 @@ func (sc ManagementGroupsScanner) ListSubscriptions(ctx context.Context, cred az
 	for resultPager.More() {
 		pageResp, err := resultPager.NextPage(ctx)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to list management group subscriptions")
+			return nil, fmt.Errorf("failed to list management group subscriptions: %w", err)
 		}
 
 		for _, s := range pageResp.Value {
@@ -46,5 +48,5 @@ func (sc ManagementGroupsScanner) ListSubscriptions(ctx context.Context, cred az
 		result[sid] = *s.Properties.DisplayName
 	}
 
-	return result
+	return result, nil
 }
@@ -45,8 +45,7 @@ func (a *MariaScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmariadb.Server)
-				caf := strings.HasPrefix(*c.Name, "maria")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DBforMariaDB/servers", "", *c.Name, "maria")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -69,7 +68,7 @@ func (a *MariaScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmariadb.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DBforMariaDB/servers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -99,8 +98,7 @@ func (a *MariaScanner) GetDatabaseRules() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmariadb.Database)
-				caf := strings.HasPrefix(*c.Name, "mariadb")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DBforMariaDB/servers/databases", "", *c.Name, "mariadb")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -60,8 +60,7 @@ func (a *MySQLScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmysql.Server)
-				caf := strings.HasPrefix(*c.Name, "mysql")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DBforMySQL/servers", "", *c.Name, "mysql")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -84,7 +83,7 @@ func (a *MySQLScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmysql.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DBforMySQL/servers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -149,8 +148,7 @@ func (a *MySQLFlexibleScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmysqlflexibleservers.Server)
-				caf := strings.HasPrefix(*c.Name, "mysql")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DBforMySQL/flexibleServers", "", *c.Name, "mysql")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -162,7 +160,7 @@ func (a *MySQLFlexibleScanner) GetRecommendations() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armmysqlflexibleservers.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DBforMySQL/flexibleServers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
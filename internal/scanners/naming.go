@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// NamingConvention - Naming convention of a resource type, evaluated by the naming rules instead of the CAF prefix
+type NamingConvention struct {
+	ResourceType string `yaml:"resourceType"`
+	// Kind restricts the convention to a kind of the resource type, e.g. OpenAI for Cognitive Services accounts
+	Kind string `yaml:"kind,omitempty"`
+	// Pattern is a regular expression the name must match
+	Pattern string `yaml:"pattern,omitempty"`
+	// Template is a name template, e.g. <app>-<env>-<region>-st. Each <placeholder> matches letters and digits
+	Template string `yaml:"template,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// NamingConventions - Conventions of a naming policy by lower case resource type and kind
+type NamingConventions map[string]*NamingConvention
+
+var placeholder = regexp.MustCompile(`<[^<>]+>`)
+
+// LoadNamingPolicy loads the naming conventions of a YAML file. An empty file name returns no conventions: the CAF prefixes are expected
+func LoadNamingPolicy(file string) (NamingConventions, error) {
+	loaded := NamingConventions{}
+	if file == "" {
+		return loaded, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading naming policy: %s: %w", file, err)
+	}
+
+	var conventions []*NamingConvention
+	if err := yaml.Unmarshal(content, &conventions); err != nil {
+		return nil, fmt.Errorf("failed parsing naming policy: %s: %w", file, err)
+	}
+
+	for _, c := range conventions {
+		if err := c.compile(); err != nil {
+			return nil, fmt.Errorf("invalid naming policy: %s: %w", file, err)
+		}
+		loaded[namingKey(c.ResourceType, c.Kind)] = c
+	}
+	log.Info().Msgf("Loaded %d naming conventions from %s", len(loaded), file)
+	return loaded, nil
+}
+
+// CheckNamingConvention returns true when the name does not comply with the naming convention of the resource type and kind
+// in the naming policy of the scan, and the expected pattern. The CAF prefix is expected when the policy has no convention for the resource type
+func CheckNamingConvention(scanContext *ScanContext, resourceType, kind, name, prefix string) (bool, string) {
+	c, ok := scanContext.namingConvention(resourceType, kind)
+	if !ok {
+		if strings.HasPrefix(name, prefix) {
+			return false, ""
+		}
+		return true, fmt.Sprintf("Expected: %s*", prefix)
+	}
+
+	if c.re.MatchString(name) {
+		return false, ""
+	}
+	return true, fmt.Sprintf("Expected: %s", c.expected())
+}
+
+// HasNamingConvention returns true when the naming policy of the scan has a convention for the resource type and kind
+func HasNamingConvention(scanContext *ScanContext, resourceType, kind string) bool {
+	_, ok := scanContext.namingConvention(resourceType, kind)
+	return ok
+}
+
+// namingConvention returns the convention of the resource type and kind, or of the resource type
+func (c *ScanContext) namingConvention(resourceType, kind string) (*NamingConvention, bool) {
+	if c == nil || c.Rules == nil {
+		return nil, false
+	}
+	if n, ok := c.Rules.Naming[namingKey(resourceType, kind)]; ok {
+		return n, true
+	}
+	n, ok := c.Rules.Naming[namingKey(resourceType, "")]
+	return n, ok
+}
+
+// compile validates the convention and compiles its pattern or template
+func (c *NamingConvention) compile() error {
+	if c.ResourceType == "" {
+		return fmt.Errorf("naming convention without resourceType")
+	}
+	if (c.Pattern == "") == (c.Template == "") {
+		return fmt.Errorf("naming convention of %s requires either a pattern or a template", c.ResourceType)
+	}
+
+	expr := c.Pattern
+	if c.Template != "" {
+		parts := placeholder.Split(c.Template, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		expr = "^" + strings.Join(parts, "[a-zA-Z0-9]+") + "$"
+	}
+
+	var err error
+	if c.re, err = regexp.Compile(expr); err != nil {
+		return fmt.Errorf("naming convention of %s: %w", c.ResourceType, err)
+	}
+	return nil
+}
+
+func (c *NamingConvention) expected() string {
+	if c.Template != "" {
+		return c.Template
+	}
+	return c.Pattern
+}
+
+func namingKey(resourceType, kind string) string {
+	return strings.ToLower(resourceType + "|" + kind)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+)
+
+func TestCheckNamingConvention(t *testing.T) {
+	content := `
+- resourceType: Microsoft.Storage/storageAccounts
+  pattern: ^[a-z]+(dev|prod)st$
+- resourceType: Microsoft.CognitiveServices/accounts
+  template: <app>-<env>-cog
+- resourceType: Microsoft.CognitiveServices/accounts
+  kind: OpenAI
+  template: <app>-<env>-oai
+`
+	naming, err := LoadNamingPolicy(writeFile(t, "naming.yaml", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanContext := &ScanContext{Rules: &Rules{Naming: naming}}
+
+	tests := []struct {
+		resourceType, kind, name, prefix string
+		broken                           bool
+		result                           string
+	}{
+		{"Microsoft.Storage/storageAccounts", "", "contosoprodst", "st", false, ""},
+		{"Microsoft.Storage/storageAccounts", "", "stcontoso", "st", true, "Expected: ^[a-z]+(dev|prod)st$"},
+		{"Microsoft.CognitiveServices/accounts", "openai", "chat-prod-oai", "oai", false, ""},
+		{"Microsoft.CognitiveServices/accounts", "OpenAI", "chat-prod-cog", "oai", true, "Expected: <app>-<env>-oai"},
+		{"Microsoft.CognitiveServices/accounts", "Face", "chat-prod-cog", "face", false, ""},
+		{"Microsoft.KeyVault/vaults", "", "kvcontoso", "kv", false, ""},
+		{"Microsoft.KeyVault/vaults", "", "contoso", "kv", true, "Expected: kv*"},
+	}
+	for _, tt := range tests {
+		broken, result := CheckNamingConvention(scanContext, tt.resourceType, tt.kind, tt.name, tt.prefix)
+		if broken != tt.broken || result != tt.result {
+			t.Errorf("%s %s: expected %t %q, got %t %q", tt.resourceType, tt.name, tt.broken, tt.result, broken, result)
+		}
+	}
+}
+
+func TestCheckNamingConvention_NoPolicy(t *testing.T) {
+	if broken, result := CheckNamingConvention(&ScanContext{}, "Microsoft.Storage/storageAccounts", "", "contosoprodst", "st"); !broken || result != "Expected: st*" {
+		t.Errorf("expected the CAF prefix without a naming policy, got %t %q", broken, result)
+	}
+}
+
+func TestLoadNamingPolicy_Invalid(t *testing.T) {
+	contents := []string{
+		"- {resourceType: Microsoft.Storage/storageAccounts}",
+		"- {resourceType: Microsoft.Storage/storageAccounts, pattern: '^st', template: '<app>-st'}",
+		"- {resourceType: Microsoft.Storage/storageAccounts, pattern: '('}",
+		"- {pattern: '^st'}",
+	}
+	for _, content := range contents {
+		if _, err := LoadNamingPolicy(writeFile(t, "naming.yaml", content)); err == nil {
+			t.Errorf("expected an error for %s", content)
+		}
+	}
+}
@@ -46,8 +46,7 @@ func (a *NatGatewayScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.NatGateway)
-				caf := strings.HasPrefix(*c.Name, "ng")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/natGateways", "", *c.Name, "ng")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -59,7 +58,7 @@ func (a *NatGatewayScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.NatGateway)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/natGateways", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
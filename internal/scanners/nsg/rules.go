@@ -46,8 +46,7 @@ func (a *NSGScanner) GetRecommendations() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.SecurityGroup)
-				caf := strings.HasPrefix(*c.Name, "nsg")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/networkSecurityGroups", "", *c.Name, "nsg")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -59,7 +58,7 @@ func (a *NSGScanner) GetRecommendations() map[string]scanners.AzqrRecommendation
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.SecurityGroup)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/networkSecurityGroups", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -4,8 +4,6 @@
 package nw
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 )
@@ -33,8 +31,7 @@ func (a *NetworkWatcherScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.Watcher)
-				caf := strings.HasPrefix(*c.Name, "nw")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/networkWatchers", "", *c.Name, "nw")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -46,7 +43,7 @@ func (a *NetworkWatcherScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.Watcher)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/networkWatchers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
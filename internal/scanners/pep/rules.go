@@ -4,8 +4,6 @@
 package pep
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 )
@@ -33,8 +31,7 @@ func (a *PrivateEndpointScanner) GetRecommendations() map[string]scanners.AzqrRe
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.PrivateEndpoint)
-				caf := strings.HasPrefix(*c.Name, "pep")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/privateEndpoints", "", *c.Name, "pep")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -46,7 +43,7 @@ func (a *PrivateEndpointScanner) GetRecommendations() map[string]scanners.AzqrRe
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.PrivateEndpoint)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/privateEndpoints", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azqr/internal/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// SourcePolicy is the source of the results of the Rego policies
+const SourcePolicy = "OPA"
+
+// LoadPolicies loads the Rego policies of a directory, evaluated with the rules of the scanners.
+// An empty directory returns a nil engine: no policies are evaluated
+func LoadPolicies(ctx context.Context, dir string) (*policy.Engine, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	engine, err := policy.Load(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Loaded policies %s from %s", strings.Join(engine.Packages(), ", "), dir)
+	return engine, nil
+}
+
+// evaluatePolicies returns a not compliant result for each violation of the Rego policies.
+// Violations with the same id are reported as a single result
+func evaluatePolicies(engine *policy.Engine, document map[string]interface{}, scanContext *ScanContext) map[string]AzqrResult {
+	results := map[string]AzqrResult{}
+	if engine == nil {
+		return results
+	}
+
+	ctx := context.Background()
+	if scanContext != nil && scanContext.Ctx != nil {
+		ctx = scanContext.Ctx
+	}
+
+	resourceType, _ := document["type"].(string)
+	violations, err := engine.Eval(ctx, document)
+	if err != nil {
+		// the failure is reported with the id of the policy package, the default id of its violations
+		log.Warn().Err(err).Msgf("Failed to evaluate policies for %v", document["id"])
+		id := SourcePolicy
+		var evalErr *policy.EvalError
+		if errors.As(err, &evalErr) {
+			id = evalErr.Package
+		}
+		results[id] = AzqrResult{
+			RecommendationID: id,
+			ResourceType:     resourceType,
+			Recommendation:   fmt.Sprintf("Rego policy %s", id),
+			Category:         CategoryOtherBestPractices,
+			Impact:           ImpactMedium,
+			Status:           StatusError,
+			Result:           err.Error(),
+			Source:           SourcePolicy,
+		}
+		return results
+	}
+
+	for _, v := range violations {
+		if scanContext != nil && scanContext.Filters != nil && scanContext.Filters.Azqr != nil &&
+			scanContext.Filters.Azqr.IsRecommendationExcluded(v.ID) {
+			continue
+		}
+
+		if r, ok := results[v.ID]; ok {
+			r.Result = strings.Trim(r.Result+"; "+v.Result, "; ")
+			results[v.ID] = r
+			continue
+		}
+
+		category, err := parseCategory(v.Category)
+		if err != nil {
+			category = CategoryOtherBestPractices
+		}
+		impact, err := parseImpact(v.Impact)
+		if err != nil {
+			impact = ImpactMedium
+		}
+
+		results[v.ID] = AzqrResult{
+			RecommendationID: v.ID,
+			ResourceType:     resourceType,
+			Recommendation:   v.Message,
+			Category:         category,
+			Impact:           impact,
+			LearnMoreUrl:     v.LearnMoreUrl,
+			NotCompliant:     true,
+			Status:           StatusNotCompliant,
+			Result:           v.Result,
+			Source:           SourcePolicy,
+		}
+	}
+	return results
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+func TestRecommendationEngine_Policies(t *testing.T) {
+	dir := t.TempDir()
+	content := `package contoso
+
+deny contains {"id": "contoso-001", "msg": "Storage Account should have an owner tag", "category": "Governance", "impact": "Low"} if {
+	not input.tags.owner
+}
+
+deny contains {"id": "contoso-002", "msg": "Excluded"} if {
+	true
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "contoso.rego"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := LoadPolicies(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := &Filters{Azqr: &AzqrFilter{xRecommendations: map[string]bool{"contoso-002": true}}}
+	scanContext := &ScanContext{Filters: filters, Rules: &Rules{Policies: policies}}
+	account := &armstorage.Account{
+		ID:   to.Ptr("/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"),
+		Type: to.Ptr("Microsoft.Storage/storageAccounts"),
+	}
+
+	engine := RecommendationEngine{}
+	results := engine.EvaluateRecommendations(map[string]AzqrRecommendation{}, account, scanContext)
+
+	r, ok := results["contoso-001"]
+	if !ok {
+		t.Fatal("expected a result for the policy violation")
+	}
+	if !r.NotCompliant || r.Source != SourcePolicy || r.Category != CategoryGovernance || r.Impact != ImpactLow ||
+		r.ResourceType != "Microsoft.Storage/storageAccounts" {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if _, ok := results["contoso-002"]; ok {
+		t.Error("expected the excluded recommendation to be skipped")
+	}
+
+	account.Tags = map[string]*string{"owner": to.Ptr("contoso")}
+	results = engine.EvaluateRecommendations(map[string]AzqrRecommendation{}, account, scanContext)
+	if _, ok := results["contoso-001"]; ok {
+		t.Error("expected no violation for a compliant resource")
+	}
+}
+
+func TestRecommendationEngine_PolicyError(t *testing.T) {
+	dir := t.TempDir()
+	content := `package contoso
+
+deny contains 42 if {
+	true
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "contoso.rego"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := LoadPolicies(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account := &armstorage.Account{
+		ID:   to.Ptr("/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"),
+		Type: to.Ptr("Microsoft.Storage/storageAccounts"),
+	}
+	engine := RecommendationEngine{}
+	results := engine.EvaluateRecommendations(map[string]AzqrRecommendation{}, account, &ScanContext{Ctx: context.Background(), Rules: &Rules{Policies: policies}})
+
+	r, ok := results["contoso"]
+	if !ok || r.Status != StatusError || r.NotCompliant || r.Source != SourcePolicy {
+		t.Errorf("expected an error result for the policy package, got %+v", r)
+	}
+}
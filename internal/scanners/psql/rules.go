@@ -60,8 +60,7 @@ func (a *PostgreScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armpostgresql.Server)
-				caf := strings.HasPrefix(*c.Name, "psql")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DBforPostgreSQL/servers", "", *c.Name, "psql")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -73,7 +72,7 @@ func (a *PostgreScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armpostgresql.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DBforPostgreSQL/servers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -162,8 +161,7 @@ func (a *PostgreFlexibleScanner) GetRecommendations() map[string]scanners.AzqrRe
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armpostgresqlflexibleservers.Server)
-				caf := strings.HasPrefix(*c.Name, "psql")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.DBforPostgreSQL/flexibleServers", "", *c.Name, "psql")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -175,7 +173,7 @@ func (a *PostgreFlexibleScanner) GetRecommendations() map[string]scanners.AzqrRe
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armpostgresqlflexibleservers.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.DBforPostgreSQL/flexibleServers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -46,8 +46,7 @@ func (a *RedisScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armredis.ResourceInfo)
-				caf := strings.HasPrefix(*c.Name, "redis")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Cache/Redis", "", *c.Name, "redis")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -59,7 +58,7 @@ func (a *RedisScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armredis.ResourceInfo)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Cache/Redis", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -86,6 +85,11 @@ func (a *RedisScanner) GetRecommendations() map[string]scanners.AzqrRecommendati
 				return c.Properties.MinimumTLSVersion == nil || *c.Properties.MinimumTLSVersion != armredis.TLSVersionOne2, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-cache-for-redis/cache-remove-tls-10-11",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az redis update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --set minimumTlsVersion=1.2",
+				Bicep:     "properties: { minimumTlsVersion: '1.2' }",
+				Terraform: `minimum_tls_version = "1.2"`,
+			},
 		},
 	}
 }
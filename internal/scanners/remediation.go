@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+type (
+	// Remediation - Templates of the fix of a recommendation, as an az CLI command, a Bicep property patch
+	// or a Terraform attribute. The templates are Go templates executed with the RemediationTarget
+	// of the non compliant resource, e.g. az storage account update --ids {{shellquote .ResourceID}} --https-only true.
+	// The shellquote function quotes the values passed to the az CLI
+	Remediation struct {
+		AzCli     string `yaml:"azCli,omitempty" json:"azCli,omitempty"`
+		Bicep     string `yaml:"bicep,omitempty" json:"bicep,omitempty"`
+		Terraform string `yaml:"terraform,omitempty" json:"terraform,omitempty"`
+	}
+
+	// RemediationTarget - Resource whose fix is rendered by the remediation templates
+	RemediationTarget struct {
+		SubscriptionID string
+		ResourceGroup  string
+		ResourceType   string
+		Name           string
+		ResourceID     string
+	}
+)
+
+// Render executes the templates of the remediation for a resource
+func (r *Remediation) Render(target RemediationTarget) (*Remediation, error) {
+	rendered := &Remediation{}
+	var err error
+	if rendered.AzCli, err = renderTemplate("azCli", r.AzCli, target); err != nil {
+		return nil, err
+	}
+	if rendered.Bicep, err = renderTemplate("bicep", r.Bicep, target); err != nil {
+		return nil, err
+	}
+	if rendered.Terraform, err = renderTemplate("terraform", r.Terraform, target); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// String returns the fixes of a rendered remediation, one per line
+func (r *Remediation) String() string {
+	lines := []string{}
+	if r.AzCli != "" {
+		lines = append(lines, r.AzCli)
+	}
+	if r.Bicep != "" {
+		lines = append(lines, "Bicep: "+r.Bicep)
+	}
+	if r.Terraform != "" {
+		lines = append(lines, "Terraform: "+r.Terraform)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RemediationTarget returns the resource of the result, as the target of the remediation templates
+func (r *AzqrServiceResult) RemediationTarget() RemediationTarget {
+	return RemediationTarget{
+		SubscriptionID: r.SubscriptionID,
+		ResourceGroup:  r.ResourceGroup,
+		ResourceType:   r.Type,
+		Name:           r.ServiceName,
+		ResourceID:     fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s/%s", r.SubscriptionID, r.ResourceGroup, r.Type, r.ServiceName),
+	}
+}
+
+// RemediationTarget returns the resource of the result, as the target of the remediation templates
+func (r *AprlResult) RemediationTarget() RemediationTarget {
+	return RemediationTarget{
+		SubscriptionID: r.SubscriptionID,
+		ResourceGroup:  r.ResourceGroup,
+		ResourceType:   r.ResourceType,
+		Name:           r.Name,
+		ResourceID:     r.ResourceID,
+	}
+}
+
+// HasAutomation returns true when the AutomationAvailable field of an APRL recommendation is set
+func (r *AprlRecommendation) HasAutomation() bool {
+	switch strings.ToLower(strings.TrimSpace(r.AutomationAvailable)) {
+	case "", "false", "no", "n/a":
+		return false
+	}
+	return true
+}
+
+// shellSafe matches the values that are not quoted by shellquote
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+-]+$`)
+
+// shellQuote quotes a value as a single word of a POSIX shell command
+func shellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func renderTemplate(name, text string, target RemediationTarget) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	t, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"shellquote": shellQuote,
+	}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s remediation template: %w", name, err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, target); err != nil {
+		return "", fmt.Errorf("failed to render %s remediation template: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
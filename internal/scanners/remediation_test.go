@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import "testing"
+
+func TestRemediation_Render(t *testing.T) {
+	target := RemediationTarget{
+		SubscriptionID: "0000",
+		ResourceGroup:  "rg",
+		ResourceType:   "Microsoft.ContainerRegistry/registries",
+		Name:           "cr",
+		ResourceID:     "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.ContainerRegistry/registries/cr",
+	}
+
+	r := &Remediation{
+		AzCli: "az acr update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --admin-enabled false",
+		Bicep: "properties: { adminUserEnabled: false }",
+	}
+	rendered, err := r.Render(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "az acr update --name cr --resource-group rg --admin-enabled false\nBicep: properties: { adminUserEnabled: false }"
+	if rendered.String() != expected {
+		t.Errorf("expected %q, got %q", expected, rendered.String())
+	}
+
+	for _, invalid := range []string{"az acr update --name {{.Name", "az acr update --name {{.Unknown}}"} {
+		r := &Remediation{AzCli: invalid}
+		if _, err := r.Render(target); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestRemediation_ShellQuote(t *testing.T) {
+	target := RemediationTarget{ResourceGroup: "rg; rm -rf ~", Name: "it's"}
+	r := &Remediation{AzCli: "az resource show --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}}"}
+	rendered, err := r.Render(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `az resource show --name 'it'\''s' --resource-group 'rg; rm -rf ~'`
+	if rendered.AzCli != expected {
+		t.Errorf("expected %q, got %q", expected, rendered.AzCli)
+	}
+}
+
+func TestAprlRecommendation_HasAutomation(t *testing.T) {
+	tests := map[string]bool{"": false, "false": false, "False": false, "true": true, "Azure CLI": true}
+	for value, expected := range tests {
+		r := AprlRecommendation{AutomationAvailable: value}
+		if r.HasAutomation() != expected {
+			t.Errorf("%q: expected %t", value, expected)
+		}
+	}
+}
@@ -2,26 +2,35 @@ package scanners
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/Azure/azqr/internal/graph"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/rs/zerolog/log"
 )
 
 type ResourceScanner struct{}
 
-func (sc ResourceScanner) GetAllResources(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters) ([]*Resource, []*Resource) {
+// GetAllResources returns the resources in scope and the resources excluded by the filters
+func (sc ResourceScanner) GetAllResources(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters, options *arm.ClientOptions) ([]*Resource, []*Resource, error) {
 	LogResourceTypeScan("Resources")
 
-	graphClient := graph.NewGraphQuery(cred)
-	query := "resources | project id, subscriptionId, resourceGroup, location, type, name, sku.name, sku.tier, kind"
+	graphClient, err := graph.NewGraphQuery(cred, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := "resources | project id, subscriptionId, resourceGroup, location, type, name, sku.name, sku.tier, kind, tags"
 	log.Debug().Msg(query)
 	subs := make([]*string, 0, len(subscriptions))
 	for s := range subscriptions {
 		subs = append(subs, &s)
 	}
-	result := graphClient.Query(ctx, query, subs)
+	result, err := graphClient.Query(ctx, query, subs)
+	if err != nil {
+		return nil, nil, err
+	}
 	resources := []*Resource{}
 	excludedResources := []*Resource{}
 	if result.Data != nil {
@@ -65,7 +74,8 @@ func (sc ResourceScanner) GetAllResources(ctx context.Context, cred azcore.Token
 						Name:           m["name"].(string),
 						SkuName:        skuName,
 						SkuTier:        skuTier,
-						Kind:           kind})
+						Kind:           kind,
+						Tags:           toTags(m["tags"])})
 
 				continue
 			}
@@ -81,23 +91,69 @@ func (sc ResourceScanner) GetAllResources(ctx context.Context, cred azcore.Token
 					Name:           m["name"].(string),
 					SkuName:        skuName,
 					SkuTier:        skuTier,
-					Kind:           kind})
+					Kind:           kind,
+					Tags:           toTags(m["tags"])})
 		}
 	}
-	return resources, excludedResources
+	return resources, excludedResources, nil
 }
 
-func (sc ResourceScanner) GetCountPerResourceType(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, recommendations map[string]map[string]AprlRecommendation, filters *Filters) []ResourceTypeCount {
+// LoadTagFilters excludes the resources whose tags do not match the tag filters.
+// The filters are evaluated by Resource Graph, which only returns the matching resources
+func (sc ResourceScanner) LoadTagFilters(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *Filters, options *arm.ClientOptions) error {
+	if !filters.Azqr.HasTagFilters() {
+		return nil
+	}
+
+	LogResourceTypeScan("Tag Filters")
+
+	graphClient, err := graph.NewGraphQuery(cred, options)
+	if err != nil {
+		return err
+	}
+	query := filters.Azqr.TagQuery()
+	log.Debug().Msg(query)
+	subs := make([]*string, 0, len(subscriptions))
+	for s := range subscriptions {
+		subs = append(subs, &s)
+	}
+	result, err := graphClient.Query(ctx, query, subs)
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	if result.Data != nil {
+		for _, row := range result.Data {
+			m := row.(map[string]interface{})
+			if id, ok := m["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	filters.Azqr.SetTaggedResources(ids)
+	log.Info().Msgf("%d resources match the tag filters", len(ids))
+	return nil
+}
+
+// GetCountPerResourceType returns the number of resources per subscription and resource type
+func (sc ResourceScanner) GetCountPerResourceType(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, recommendations map[string]map[string]AprlRecommendation, filters *Filters, options *arm.ClientOptions) ([]ResourceTypeCount, error) {
 	LogResourceTypeScan("Resource Count per Subscription and Type")
 
-	graphClient := graph.NewGraphQuery(cred)
+	graphClient, err := graph.NewGraphQuery(cred, options)
+	if err != nil {
+		return nil, err
+	}
 	query := "resources | summarize count() by subscriptionId, type | order by subscriptionId, type"
 	log.Debug().Msg(query)
 	subs := make([]*string, 0, len(subscriptions))
 	for s := range subscriptions {
 		subs = append(subs, &s)
 	}
-	result := graphClient.Query(ctx, query, subs)
+	result, err := graphClient.Query(ctx, query, subs)
+	if err != nil {
+		return nil, err
+	}
 	resources := []ResourceTypeCount{}
 	if result.Data != nil {
 		for _, row := range result.Data {
@@ -118,7 +174,18 @@ func (sc ResourceScanner) GetCountPerResourceType(ctx context.Context, cred azco
 			})
 		}
 	}
-	return resources
+	return resources, nil
+}
+
+// toTags converts the tags of a Resource Graph row
+func toTags(v interface{}) map[string]string {
+	tags := map[string]string{}
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, t := range m {
+			tags[k] = fmt.Sprint(t)
+		}
+	}
+	return tags
 }
 
 func (sc ResourceScanner) isAvailableInAPRL(resourceType string, recommendations map[string]map[string]AprlRecommendation) string {
@@ -22,13 +22,33 @@ func (a *ResourceGroupScanner) Init(config *scanners.ScannerConfig) error {
 
 // Scan - Scans all Resource Groups
 func (a *ResourceGroupScanner) Scan(scanContext *scanners.ScanContext) ([]scanners.AzqrServiceResult, error) {
-	return []scanners.AzqrServiceResult{}, nil
+	scanners.LogSubscriptionScan(a.config.SubscriptionID, a.ResourceTypes()[0])
+
+	resourceGroups, err := scanners.ListResourceGroup(a.config.Ctx, a.config.Cred, a.config.SubscriptionID, a.config.ClientOptions)
+	if err != nil {
+		return nil, err
+	}
+	engine := scanners.RecommendationEngine{}
+	rules := a.GetRecommendations()
+	results := []scanners.AzqrServiceResult{}
+
+	for _, g := range resourceGroups {
+		rr := engine.EvaluateRecommendations(rules, g, scanContext)
+
+		results = append(results, scanners.AzqrServiceResult{
+			SubscriptionID:   a.config.SubscriptionID,
+			SubscriptionName: a.config.SubscriptionName,
+			ResourceGroup:    *g.Name,
+			ServiceName:      *g.Name,
+			ID:               *g.ID,
+			Type:             *g.Type,
+			Location:         *g.Location,
+			Recommendations:  rr,
+		})
+	}
+	return results, nil
 }
 
 func (a *ResourceGroupScanner) ResourceTypes() []string {
 	return []string{"Microsoft.Resources/resourceGroups"}
 }
-
-func (a *ResourceGroupScanner) GetRecommendations() map[string]scanners.AzqrRecommendation {
-	return map[string]scanners.AzqrRecommendation{}
-}
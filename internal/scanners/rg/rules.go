@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rg
+
+import (
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// GetRecommendations - Returns the rules for the ResourceGroupScanner
+func (a *ResourceGroupScanner) GetRecommendations() map[string]scanners.AzqrRecommendation {
+	return map[string]scanners.AzqrRecommendation{
+		"rg-007": {
+			RecommendationID: "rg-007",
+			ResourceType:     "Microsoft.Resources/resourceGroups",
+			Category:         scanners.CategoryGovernance,
+			Recommendation:   "Resource Group should have tags",
+			Impact:           scanners.ImpactLow,
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armresources.ResourceGroup)
+				return scanners.CheckTags(scanContext, "Microsoft.Resources/resourceGroups", c.ID, c.Tags)
+			},
+			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
+		},
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+func TestResourceGroupScanner_Rules(t *testing.T) {
+	type fields struct {
+		rule        string
+		target      interface{}
+		scanContext *scanners.ScanContext
+	}
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   want
+	}{
+		{
+			name: "ResourceGroupScanner tags",
+			fields: fields{
+				rule: "rg-007",
+				target: &armresources.ResourceGroup{
+					ID:   to.Ptr("/subscriptions/0000/resourceGroups/rg"),
+					Tags: map[string]*string{"owner": to.Ptr("contoso")},
+				},
+				scanContext: &scanners.ScanContext{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "ResourceGroupScanner without tags",
+			fields: fields{
+				rule: "rg-007",
+				target: &armresources.ResourceGroup{
+					ID: to.Ptr("/subscriptions/0000/resourceGroups/rg"),
+				},
+				scanContext: &scanners.ScanContext{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResourceGroupScanner{}
+			rules := s.GetRecommendations()
+			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResourceGroupScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -65,8 +65,7 @@ func (a *ServiceBusScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armservicebus.SBNamespace)
-				caf := strings.HasPrefix(*c.Name, "sb")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.ServiceBus/namespaces", "", *c.Name, "sb")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -78,7 +77,7 @@ func (a *ServiceBusScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armservicebus.SBNamespace)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.ServiceBus/namespaces", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -94,6 +93,11 @@ func (a *ServiceBusScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 				return !localAuth, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/service-bus-messaging/service-bus-sas",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az servicebus namespace update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --disable-local-auth true",
+				Bicep:     "properties: { disableLocalAuth: true }",
+				Terraform: "local_auth_enabled = false",
+			},
 		},
 	}
 }
@@ -4,6 +4,7 @@
 package scanners
 
 import (
+	"reflect"
 	"sort"
 )
 
@@ -23,3 +24,13 @@ func GetScanners() ([]string, []IAzureScanner) {
 	}
 	return keys, scanners
 }
+
+// NewScannerInstance returns a new, uninitialized instance of the same type as the given scanner.
+// Scanners keep their configuration after Init, so concurrent scans need their own instances.
+func NewScannerInstance(s IAzureScanner) IAzureScanner {
+	t := reflect.TypeOf(s)
+	if t.Kind() != reflect.Ptr {
+		return s
+	}
+	return reflect.New(t.Elem()).Interface().(IAzureScanner)
+}
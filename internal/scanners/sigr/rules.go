@@ -59,8 +59,7 @@ func (a *SignalRScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsignalr.ResourceInfo)
-				caf := strings.HasPrefix(*c.Name, "sigr")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.SignalRService/SignalR", "", *c.Name, "sigr")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -72,7 +71,7 @@ func (a *SignalRScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsignalr.ResourceInfo)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.SignalRService/SignalR", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
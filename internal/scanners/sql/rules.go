@@ -44,8 +44,7 @@ func (a *SQLScanner) getServerRules() map[string]scanners.AzqrRecommendation {
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.Server)
-				caf := strings.HasPrefix(*c.Name, "sql")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Sql/servers", "", *c.Name, "sql")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -57,7 +56,7 @@ func (a *SQLScanner) getServerRules() map[string]scanners.AzqrRecommendation {
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.Server)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Sql/servers", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -72,6 +71,11 @@ func (a *SQLScanner) getServerRules() map[string]scanners.AzqrRecommendation {
 				return c.Properties.MinimalTLSVersion == nil || *c.Properties.MinimalTLSVersion != "1.2", ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-sql/database/connectivity-settings?view=azuresql&tabs=azure-portal#minimal-tls-version",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az sql server update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --minimal-tls-version 1.2",
+				Bicep:     "properties: { minimalTlsVersion: '1.2' }",
+				Terraform: `minimum_tls_version = "1.2"`,
+			},
 		},
 	}
 }
@@ -114,8 +118,10 @@ func (a *SQLScanner) getDatabaseRules() map[string]scanners.AzqrRecommendation {
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.Database)
-				caf := *c.Name == "master" || strings.HasPrefix(*c.Name, "sqldb")
-				return !caf, ""
+				if *c.Name == "master" {
+					return false, ""
+				}
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Sql/servers/databases", "", *c.Name, "sqldb")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -127,7 +133,7 @@ func (a *SQLScanner) getDatabaseRules() map[string]scanners.AzqrRecommendation {
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.Database)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Sql/servers/databases", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -144,8 +150,7 @@ func (a *SQLScanner) getPoolRules() map[string]scanners.AzqrRecommendation {
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.ElasticPool)
-				caf := strings.HasPrefix(*c.Name, "sqlep")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Sql/servers/elasticPools", "", *c.Name, "sqlep")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -157,7 +162,7 @@ func (a *SQLScanner) getPoolRules() map[string]scanners.AzqrRecommendation {
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.ElasticPool)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Sql/servers/elasticPools", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -62,8 +62,7 @@ func (a *StorageScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armstorage.Account)
-				caf := strings.HasPrefix(*c.Name, "st")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Storage/storageAccounts", "", *c.Name, "st")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -79,6 +78,11 @@ func (a *StorageScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 				return !h, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/storage/common/storage-require-secure-transfer",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az storage account update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --https-only true",
+				Bicep:     "properties: { supportsHttpsTrafficOnly: true }",
+				Terraform: "https_traffic_only_enabled = true",
+			},
 		},
 		"st-008": {
 			RecommendationID: "st-008",
@@ -88,7 +92,7 @@ func (a *StorageScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armstorage.Account)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Storage/storageAccounts", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -103,6 +107,11 @@ func (a *StorageScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 				return c.Properties.MinimumTLSVersion == nil || *c.Properties.MinimumTLSVersion != armstorage.MinimumTLSVersionTLS12, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/storage/common/transport-layer-security-configure-minimum-version?tabs=portal",
+			Remediation: &scanners.Remediation{
+				AzCli:     "az storage account update --name {{shellquote .Name}} --resource-group {{shellquote .ResourceGroup}} --min-tls-version TLS1_2",
+				Bicep:     "properties: { minimumTlsVersion: 'TLS1_2' }",
+				Terraform: `min_tls_version = "TLS1_2"`,
+			},
 		},
 		"st-010": {
 			RecommendationID: "st-010",
@@ -122,13 +131,17 @@ func (a *StorageScanner) GetRecommendations() map[string]scanners.AzqrRecommenda
 			Category:         scanners.CategoryDisasterRecovery,
 			Recommendation:   "Storage Account should have soft delete enabled",
 			Impact:           scanners.ImpactMedium,
-			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
-				broken := false
-				broken = scanContext.BlobServiceProperties != nil && (scanContext.BlobServiceProperties.BlobServiceProperties.BlobServiceProperties.ContainerDeleteRetentionPolicy == nil ||
-					scanContext.BlobServiceProperties.BlobServiceProperties.BlobServiceProperties.ContainerDeleteRetentionPolicy.Enabled == nil ||
-					!*scanContext.BlobServiceProperties.BlobServiceProperties.BlobServiceProperties.ContainerDeleteRetentionPolicy.Enabled)
+			EvalStatus: func(target interface{}, scanContext *scanners.ScanContext) (scanners.RecommendationStatus, string) {
+				// accounts without a blob service, e.g. FileStorage accounts, have no container soft delete
+				if scanContext.BlobServiceProperties == nil || scanContext.BlobServiceProperties.BlobServiceProperties.BlobServiceProperties == nil {
+					return scanners.StatusNotApplicable, ""
+				}
 
-				return broken, ""
+				policy := scanContext.BlobServiceProperties.BlobServiceProperties.BlobServiceProperties.ContainerDeleteRetentionPolicy
+				if policy == nil || policy.Enabled == nil || !*policy.Enabled {
+					return scanners.StatusNotCompliant, ""
+				}
+				return scanners.StatusCompliant, ""
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/well-architected/service-guides/storage-accounts/reliability",
 		},
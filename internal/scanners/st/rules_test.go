@@ -175,9 +175,10 @@ func TestStorageScanner_Rules(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &StorageScanner{}
 			rules := s.GetRecommendations()
-			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
+			r := rules[tt.fields.rule]
+			status, w := r.Evaluate(tt.fields.target, tt.fields.scanContext)
 			got := want{
-				broken: b,
+				broken: status == scanners.StatusNotCompliant,
 				result: w,
 			}
 			if !reflect.DeepEqual(got, tt.want) {
@@ -186,3 +187,12 @@ func TestStorageScanner_Rules(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageScanner_SoftDeleteNotApplicable(t *testing.T) {
+	s := &StorageScanner{}
+	r := s.GetRecommendations()["st-011"]
+	status, _ := r.Evaluate(&armstorage.Account{Properties: &armstorage.AccountProperties{}}, &scanners.ScanContext{})
+	if status != scanners.StatusNotApplicable {
+		t.Errorf("expected st-011 to be not applicable without a blob service, got %s", status)
+	}
+}
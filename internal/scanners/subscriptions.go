@@ -2,6 +2,7 @@ package scanners
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -12,10 +13,11 @@ import (
 
 type SubcriptionScanner struct{}
 
-func (sc SubcriptionScanner) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, subscriptionID string, filters *Filters, options *arm.ClientOptions) map[string]string {
+// ListSubscriptions returns the enabled subscriptions in scope. Key is subscription ID, value is subscription name
+func (sc SubcriptionScanner) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, subscriptionID string, filters *Filters, options *arm.ClientOptions) (map[string]string, error) {
 	client, err := armsubscription.NewSubscriptionsClient(cred, options)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create subscriptions client")
+		return nil, fmt.Errorf("failed to create subscriptions client: %w", err)
 	}
 
 	resultPager := client.NewListPager(nil)
@@ -24,7 +26,7 @@ func (sc SubcriptionScanner) ListSubscriptions(ctx context.Context, cred azcore.
 	for resultPager.More() {
 		pageResp, err := resultPager.NextPage(ctx)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to list subscriptions")
+			return nil, fmt.Errorf("failed to list subscriptions: %w", err)
 		}
 
 		for _, s := range pageResp.Value {
@@ -49,5 +51,5 @@ func (sc SubcriptionScanner) ListSubscriptions(ctx context.Context, cred azcore.
 		}
 	}
 
-	return result
+	return result, nil
 }
@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// Suppression - Accepted risk exception of a recommendation for resources, resource groups or tagged resources.
+	// Suppressed findings are reported in the Suppressed table until the suppression expires
+	Suppression struct {
+		RecommendationID string            `yaml:"recommendationId"`
+		Resources        []string          `yaml:"resources,flow"`
+		ResourceGroups   []string          `yaml:"resourceGroups,flow"`
+		Tags             map[string]string `yaml:"tags"`
+		Justification    string            `yaml:"justification"`
+		Owner            string            `yaml:"owner"`
+		Approver         string            `yaml:"approver"`
+		// Expires is the last day of the suppression (YYYY-MM-DD)
+		Expires string `yaml:"expires"`
+		expires time.Time
+	}
+
+	// SuppressedResult - Finding suppressed by a suppression
+	SuppressedResult struct {
+		Source           string
+		RecommendationID string
+		Recommendation   string
+		ResourceType     string
+		ResourceID       string
+		SubscriptionID   string
+		SubscriptionName string
+		ResourceGroup    string
+		Name             string
+		Justification    string
+		Owner            string
+		Approver         string
+		Expires          string
+		TenantID         string
+	}
+)
+
+// compile validates the suppression and parses its expiry date
+func (s *Suppression) compile() error {
+	if s.RecommendationID == "" {
+		return fmt.Errorf("suppression without recommendationId")
+	}
+	if s.Justification == "" || s.Expires == "" {
+		return fmt.Errorf("suppression of %s requires a justification and an expiry date", s.RecommendationID)
+	}
+	if len(s.Resources) == 0 && len(s.ResourceGroups) == 0 && len(s.Tags) == 0 {
+		return fmt.Errorf("suppression of %s requires resources, resourceGroups or tags", s.RecommendationID)
+	}
+
+	var err error
+	if s.expires, err = time.Parse("2006-01-02", s.Expires); err != nil {
+		return fmt.Errorf("suppression of %s has an invalid expiry date %s. Use the YYYY-MM-DD format", s.RecommendationID, s.Expires)
+	}
+	return nil
+}
+
+// Expired returns true when the suppression expired at the given time. A suppression is valid until the end of its expiry date
+func (s *Suppression) Expired(now time.Time) bool {
+	return !now.Before(s.expires.AddDate(0, 0, 1))
+}
+
+// Matches returns true when the suppression applies to the recommendation and resource
+func (s *Suppression) Matches(recommendationID, resourceID string, tags map[string]string) bool {
+	if !strings.EqualFold(s.RecommendationID, recommendationID) {
+		return false
+	}
+
+	for _, id := range s.Resources {
+		if strings.EqualFold(id, resourceID) {
+			return true
+		}
+	}
+
+	rgID := GetResourceGroupIDFromResourceID(resourceID)
+	for _, id := range s.ResourceGroups {
+		if strings.EqualFold(id, rgID) {
+			return true
+		}
+	}
+
+	if len(s.Tags) == 0 {
+		return false
+	}
+	for k, v := range s.Tags {
+		if !hasTag(tags, k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Suppression returns the active suppression of a finding, or nil when the finding is not suppressed
+func (e *AzqrFilter) Suppression(recommendationID, resourceID string, tags map[string]string, now time.Time) *Suppression {
+	for i := range e.Suppressions {
+		s := &e.Suppressions[i]
+		if !s.Expired(now) && s.Matches(recommendationID, resourceID, tags) {
+			return s
+		}
+	}
+	return nil
+}
+
+// compileSuppressions validates the suppressions and logs the expired ones, whose findings are reported again
+func (e *AzqrFilter) compileSuppressions(now time.Time) error {
+	for i := range e.Suppressions {
+		s := &e.Suppressions[i]
+		if err := s.compile(); err != nil {
+			return err
+		}
+		if s.Expired(now) {
+			log.Warn().Msgf("Suppression of %s expired on %s. Its findings are reported", s.RecommendationID, s.Expires)
+		}
+	}
+	return nil
+}
+
+// hasTag returns true when the tags contain the key (case insensitive) with the value
+func hasTag(tags map[string]string, key, value string) bool {
+	for k, v := range tags {
+		if strings.EqualFold(k, key) && v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadFilters_Suppressions(t *testing.T) {
+	content := `
+azqr:
+  suppressions:
+    - recommendationId: st-009
+      resourceGroups: [/subscriptions/0000/resourceGroups/rg-legacy]
+      justification: Legacy clients
+      owner: contoso
+      approver: security
+      expires: 2026-06-30
+    - recommendationId: st-010
+      tags: {environment: dev}
+      justification: Development only
+      expires: 2026-06-30
+`
+	filters, err := LoadFilters(writeFile(t, "filters.yaml", content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := "/subscriptions/0000/resourceGroups/RG-LEGACY/providers/Microsoft.Storage/storageAccounts/st"
+	before := time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC)
+	after := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	if s := filters.Azqr.Suppression("st-009", id, nil, before); s == nil || s.Approver != "security" {
+		t.Errorf("expected the resource group suppression, got %+v", s)
+	}
+	if s := filters.Azqr.Suppression("st-009", id, nil, after); s != nil {
+		t.Error("expected the suppression to be expired")
+	}
+	if s := filters.Azqr.Suppression("st-010", id, map[string]string{"Environment": "dev"}, before); s == nil {
+		t.Error("expected the tag suppression")
+	}
+	if s := filters.Azqr.Suppression("st-010", id, map[string]string{"environment": "prod"}, before); s != nil {
+		t.Error("expected no suppression for other tag values")
+	}
+}
+
+func TestLoadFilters_InvalidSuppression(t *testing.T) {
+	contents := []string{
+		"azqr: {suppressions: [{recommendationId: st-009, resources: [id], expires: 2026-06-30}]}",
+		"azqr: {suppressions: [{recommendationId: st-009, justification: j, expires: 2026-06-30}]}",
+		"azqr: {suppressions: [{recommendationId: st-009, resources: [id], justification: j, expires: 30/06/2026}]}",
+	}
+	for _, content := range contents {
+		if _, err := LoadFilters(writeFile(t, "filters.yaml", content), nil); err == nil {
+			t.Errorf("expected an error for %s", content)
+		}
+	}
+}
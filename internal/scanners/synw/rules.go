@@ -69,8 +69,7 @@ func (a *SynapseWorkspaceScanner) getWorkspaceRules() map[string]scanners.AzqrRe
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsynapse.Workspace)
-				caf := strings.HasPrefix(*c.Name, "synw")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, a.ResourceTypes()[0], "", *c.Name, "synw")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -82,7 +81,7 @@ func (a *SynapseWorkspaceScanner) getWorkspaceRules() map[string]scanners.AzqrRe
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsynapse.Workspace)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Synapse/workspaces", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -117,20 +116,19 @@ func (a *SynapseWorkspaceScanner) getSparkPoolRules() map[string]scanners.AzqrRe
 	return map[string]scanners.AzqrRecommendation{
 		"synsp-001": {
 			RecommendationID: "synsp-001",
-			ResourceType:     "Microsoft.Synapse workspaces/bigDataPools",
+			ResourceType:     "Microsoft.Synapse/workspaces/bigDataPools",
 			Category:         scanners.CategoryGovernance,
 			Recommendation:   "Azure Synapse Spark Pool Name should comply with naming conventions",
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsynapse.BigDataPoolResourceInfo)
-				caf := strings.HasPrefix(*c.Name, "synsp")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, a.ResourceTypes()[1], "", *c.Name, "synsp")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
 		"synsp-002": {
 			RecommendationID:   "synsp-002",
-			ResourceType:       "Microsoft.Synapse workspaces/bigDataPools",
+			ResourceType:       "Microsoft.Synapse/workspaces/bigDataPools",
 			Category:           scanners.CategoryHighAvailability,
 			Recommendation:     "Azure Synapse Spark Pool SLA",
 			RecommendationType: scanners.TypeSLA,
@@ -142,13 +140,13 @@ func (a *SynapseWorkspaceScanner) getSparkPoolRules() map[string]scanners.AzqrRe
 		},
 		"synsp-003": {
 			RecommendationID: "synsp-003",
-			ResourceType:     "Microsoft.Synapse workspaces/bigDataPools",
+			ResourceType:     "Microsoft.Synapse/workspaces/bigDataPools",
 			Category:         scanners.CategoryGovernance,
 			Recommendation:   "Azure Synapse Spark Pool should have tags",
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsynapse.BigDataPoolResourceInfo)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Synapse/workspaces/bigDataPools", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -165,8 +163,7 @@ func (a *SynapseWorkspaceScanner) getSqlPoolRules() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsynapse.SQLPool)
-				caf := strings.HasPrefix(*c.Name, "syndp")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, a.ResourceTypes()[2], "", *c.Name, "syndp")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -190,7 +187,7 @@ func (a *SynapseWorkspaceScanner) getSqlPoolRules() map[string]scanners.AzqrReco
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsynapse.SQLPool)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Synapse/workspaces/sqlPools", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
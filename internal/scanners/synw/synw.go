@@ -157,7 +157,7 @@ func (a *SynapseWorkspaceScanner) listSparkPools(resourceGroupName string, works
 func (a *SynapseWorkspaceScanner) ResourceTypes() []string {
 	return []string{
 		"Microsoft.Synapse/workspaces",
-		"Microsoft.Synapse workspaces/bigDataPools",
+		"Microsoft.Synapse/workspaces/bigDataPools",
 		"Microsoft.Synapse/workspaces/sqlPools",
 	}
 }
@@ -0,0 +1,201 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// TagPolicy - Tags required on the resources, evaluated by the tag rules instead of checking that resources have tags
+	TagPolicy struct {
+		Required []*RequiredTag `yaml:"required"`
+		// Overrides replace the required tags of resource types or resource groups. The first matching override is used
+		Overrides []*TagPolicyOverride `yaml:"overrides"`
+	}
+
+	// TagPolicyOverride - Required tags of resource types, or of resource groups given as ids or filter patterns
+	TagPolicyOverride struct {
+		ResourceTypes  []string       `yaml:"resourceTypes,flow"`
+		ResourceGroups []string       `yaml:"resourceGroups,flow"`
+		Required       []*RequiredTag `yaml:"required"`
+
+		resourceGroups        map[string]bool
+		resourceGroupPatterns []*filterPattern
+	}
+
+	// RequiredTag - Required tag with its allowed values or pattern. Any value is allowed when both are empty
+	RequiredTag struct {
+		Key     string   `yaml:"key"`
+		Values  []string `yaml:"values,flow,omitempty"`
+		Pattern string   `yaml:"pattern,omitempty"`
+
+		re *regexp.Regexp
+	}
+)
+
+// LoadTagPolicy loads the tag policy of a YAML file. An empty file name returns no policy: resources should have tags
+func LoadTagPolicy(file string) (*TagPolicy, error) {
+	if file == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading tag policy: %s: %w", file, err)
+	}
+
+	policy := &TagPolicy{}
+	if err := yaml.Unmarshal(content, policy); err != nil {
+		return nil, fmt.Errorf("failed parsing tag policy: %s: %w", file, err)
+	}
+	if err := policy.compile(); err != nil {
+		return nil, fmt.Errorf("invalid tag policy: %s: %w", file, err)
+	}
+	log.Info().Msgf("Loaded tag policy from %s", file)
+	return policy, nil
+}
+
+// CheckTags returns true when the tags of a resource do not comply with the tag policy of the scan, and the missing and invalid tags.
+// Without a tag policy, resources only need to have tags
+func CheckTags(scanContext *ScanContext, resourceType string, resourceID *string, tags map[string]*string) (bool, string) {
+	var policy *TagPolicy
+	if scanContext != nil && scanContext.Rules != nil {
+		policy = scanContext.Rules.Tags
+	}
+
+	if policy == nil {
+		return len(tags) == 0, ""
+	}
+
+	id := ""
+	if resourceID != nil {
+		id = *resourceID
+	}
+
+	missing, invalid := []string{}, []string{}
+	for _, r := range policy.required(resourceType, id) {
+		value, ok := tagValue(tags, r.Key)
+		switch {
+		case !ok:
+			missing = append(missing, r.Key)
+		case !r.allows(value):
+			invalid = append(invalid, fmt.Sprintf("%s=%s", r.Key, value))
+		}
+	}
+
+	problems := []string{}
+	if len(missing) > 0 {
+		problems = append(problems, "Missing: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "Invalid: "+strings.Join(invalid, ", "))
+	}
+	return len(problems) > 0, strings.Join(problems, "; ")
+}
+
+// required returns the required tags of a resource
+func (p *TagPolicy) required(resourceType, resourceID string) []*RequiredTag {
+	rgID := GetResourceGroupIDFromResourceID(resourceID)
+	for _, o := range p.Overrides {
+		if o.matches(resourceType, rgID) {
+			return o.Required
+		}
+	}
+	return p.Required
+}
+
+func (p *TagPolicy) compile() error {
+	if err := compileRequiredTags(p.Required); err != nil {
+		return err
+	}
+
+	for _, o := range p.Overrides {
+		if len(o.ResourceTypes) == 0 && len(o.ResourceGroups) == 0 {
+			return fmt.Errorf("override requires resourceTypes or resourceGroups")
+		}
+		if err := compileRequiredTags(o.Required); err != nil {
+			return err
+		}
+
+		var err error
+		if o.resourceGroups, o.resourceGroupPatterns, err = compileEntries(o.ResourceGroups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches returns true when the override applies to the resource type or resource group.
+// With both resource types and resource groups, both must match
+func (o *TagPolicyOverride) matches(resourceType, resourceGroupID string) bool {
+	if len(o.ResourceTypes) > 0 {
+		found := false
+		for _, t := range o.ResourceTypes {
+			found = found || strings.EqualFold(t, resourceType)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(o.ResourceGroups) > 0 && !matches(resourceGroupID, o.resourceGroups, o.resourceGroupPatterns) {
+		return false
+	}
+	return true
+}
+
+func compileRequiredTags(tags []*RequiredTag) error {
+	for _, r := range tags {
+		if r.Key == "" {
+			return fmt.Errorf("required tag without key")
+		}
+		if r.Pattern != "" {
+			var err error
+			if r.re, err = regexp.Compile(r.Pattern); err != nil {
+				return fmt.Errorf("required tag %s: %w", r.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// allows returns true when the value is one of the allowed values or matches the pattern
+func (r *RequiredTag) allows(value string) bool {
+	if len(r.Values) == 0 && r.re == nil {
+		return true
+	}
+	for _, v := range r.Values {
+		if v == value {
+			return true
+		}
+	}
+	return r.re != nil && r.re.MatchString(value)
+}
+
+// tagValue returns the value of a tag. Tag names are case insensitive
+func tagValue(tags map[string]*string, key string) (string, bool) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			if tags[k] == nil {
+				return "", true
+			}
+			return *tags[k], true
+		}
+	}
+	return "", false
+}
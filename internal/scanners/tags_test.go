@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+
+	"github.com/Azure/azqr/internal/to"
+)
+
+func TestCheckTags(t *testing.T) {
+	content := `
+required:
+  - key: owner
+  - key: costCenter
+    pattern: ^CC-[0-9]+$
+  - key: environment
+    values: [dev, test, prod]
+overrides:
+  - resourceGroups: ["/subscriptions/*/resourceGroups/rg-sandbox-*"]
+    required:
+      - key: owner
+`
+	tags, err := LoadTagPolicy(writeFile(t, "tags.yaml", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanContext := &ScanContext{Rules: &Rules{Tags: tags}}
+
+	resourceType := "Microsoft.Storage/storageAccounts"
+	id := to.Ptr("/subscriptions/0000/resourceGroups/rg-prod/providers/Microsoft.Storage/storageAccounts/st")
+	sandbox := to.Ptr("/subscriptions/0000/resourceGroups/rg-sandbox-01/providers/Microsoft.Storage/storageAccounts/st")
+
+	tests := []struct {
+		id     *string
+		tags   map[string]*string
+		broken bool
+		result string
+	}{
+		{id, map[string]*string{"Owner": to.Ptr("contoso"), "costCenter": to.Ptr("CC-42"), "environment": to.Ptr("prod")}, false, ""},
+		{id, map[string]*string{"owner": to.Ptr("contoso"), "costCenter": to.Ptr("42"), "environment": to.Ptr("qa")}, true, "Invalid: costCenter=42, environment=qa"},
+		{id, map[string]*string{"environment": to.Ptr("dev")}, true, "Missing: owner, costCenter"},
+		{sandbox, map[string]*string{"owner": to.Ptr("contoso")}, false, ""},
+		{nil, nil, true, "Missing: owner, costCenter, environment"},
+	}
+	for _, tt := range tests {
+		broken, result := CheckTags(scanContext, resourceType, tt.id, tt.tags)
+		if broken != tt.broken || result != tt.result {
+			t.Errorf("%v: expected %t %q, got %t %q", tt.tags, tt.broken, tt.result, broken, result)
+		}
+	}
+}
+
+func TestCheckTags_NoPolicy(t *testing.T) {
+	if broken, _ := CheckTags(&ScanContext{}, "Microsoft.Storage/storageAccounts", nil, nil); !broken {
+		t.Error("expected a resource without tags to be not compliant")
+	}
+	if broken, _ := CheckTags(nil, "Microsoft.Storage/storageAccounts", nil, map[string]*string{"a": to.Ptr("b")}); broken {
+		t.Error("expected a resource with tags to be compliant")
+	}
+}
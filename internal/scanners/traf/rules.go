@@ -58,8 +58,7 @@ func (a *TrafficManagerScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armtrafficmanager.Profile)
-				caf := strings.HasPrefix(*c.Name, "traf")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/trafficManagerProfiles", "", *c.Name, "traf")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -71,7 +70,7 @@ func (a *TrafficManagerScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armtrafficmanager.Profile)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/trafficManagerProfiles", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
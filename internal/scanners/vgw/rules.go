@@ -39,14 +39,14 @@ func (a *VirtualNetworkGatewayScanner) GetVirtualNetworkGatewayRules() map[strin
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.VirtualNetworkGateway)
+				prefix := "lgw"
 				switch *c.Properties.GatewayType {
 				case armnetwork.VirtualNetworkGatewayTypeVPN:
-					return !strings.HasPrefix(*c.Name, "vpng"), ""
+					prefix = "vpng"
 				case armnetwork.VirtualNetworkGatewayTypeExpressRoute:
-					return !strings.HasPrefix(*c.Name, "ergw"), ""
-				default:
-					return !strings.HasPrefix(*c.Name, "lgw"), ""
+					prefix = "ergw"
 				}
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/virtualNetworkGateways", string(*c.Properties.GatewayType), *c.Name, prefix)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -58,7 +58,7 @@ func (a *VirtualNetworkGatewayScanner) GetVirtualNetworkGatewayRules() map[strin
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.VirtualNetworkGateway)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/virtualNetworkGateways", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -6,7 +6,6 @@ package vgw
 import (
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
-	"github.com/rs/zerolog/log"
 )
 
 func init() {
@@ -34,7 +33,7 @@ func (c *VirtualNetworkGatewayScanner) Scan(scanContext *scanners.ScanContext) (
 
 	rgs, err := scanners.ListResourceGroup(c.config.Ctx, c.config.Cred, c.config.SubscriptionID, c.config.ClientOptions)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to check existence of Resource Group")
+		return nil, err
 	}
 
 	for _, rg := range rgs {
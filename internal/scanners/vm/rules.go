@@ -4,8 +4,6 @@
 package vm
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 )
@@ -43,8 +41,7 @@ func (a *VirtualMachineScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcompute.VirtualMachine)
-				caf := strings.HasPrefix(*c.Name, "vm")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Compute/virtualMachines", "", *c.Name, "vm")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -56,7 +53,7 @@ func (a *VirtualMachineScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcompute.VirtualMachine)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Compute/virtualMachines", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
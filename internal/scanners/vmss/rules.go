@@ -4,8 +4,6 @@
 package vmss
 
 import (
-	"strings"
-
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 )
@@ -39,8 +37,7 @@ func (a *VirtualMachineScaleSetScanner) GetRecommendations() map[string]scanners
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcompute.VirtualMachineScaleSet)
-				caf := strings.HasPrefix(*c.Name, "vmss")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Compute/virtualMachineScaleSets", "", *c.Name, "vmss")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -52,7 +49,7 @@ func (a *VirtualMachineScaleSetScanner) GetRecommendations() map[string]scanners
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcompute.VirtualMachineScaleSet)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Compute/virtualMachineScaleSets", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -34,8 +34,7 @@ func (a *VirtualNetworkScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.VirtualNetwork)
-				caf := strings.HasPrefix(*c.Name, "vnet")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/virtualNetworks", "", *c.Name, "vnet")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -47,7 +46,7 @@ func (a *VirtualNetworkScanner) GetRecommendations() map[string]scanners.AzqrRec
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.VirtualNetwork)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/virtualNetworks", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -69,8 +69,7 @@ func (a *VirtualWanScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.VirtualWAN)
-				caf := strings.HasPrefix(*c.Name, "vwa")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.Network/virtualWans", "", *c.Name, "vwa")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -82,7 +81,7 @@ func (a *VirtualWanScanner) GetRecommendations() map[string]scanners.AzqrRecomme
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.VirtualWAN)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.Network/virtualWans", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -0,0 +1,273 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Workload - Resources of a workload, whose SLAs are composed in a composite SLA.
+	// A resource belongs to the workload when it matches its resources, resource groups or tags.
+	// Resources, resource groups and parallel groups are ids or filter patterns (globs and regex:)
+	Workload struct {
+		Name string `yaml:"name"`
+		// Resources are resource ids
+		Resources []string `yaml:"resources,flow"`
+		// ResourceGroups are resource group ids
+		ResourceGroups []string `yaml:"resourceGroups,flow"`
+		// Tags select the resources with all the tag values
+		Tags map[string]string `yaml:"tags"`
+		// Parallel are groups of redundant resources (ids or patterns), e.g. deployments in several regions.
+		// A group is available when one of its resources is available. Other resources are in series
+		Parallel [][]string `yaml:"parallel"`
+
+		resources        map[string]bool
+		resourcePatterns []*filterPattern
+		groups           map[string]bool
+		groupPatterns    []*filterPattern
+		parallel         []workloadGroup
+	}
+
+	workloadGroup struct {
+		ids      map[string]bool
+		patterns []*filterPattern
+	}
+
+	// WorkloadSLA - Component of a workload with its SLA and the composite SLA of the workload
+	WorkloadSLA struct {
+		Workload    string
+		WorkloadSLA string
+		Component   string
+		// ResourceType of the component, empty when its resources have different types
+		ResourceType string
+		ResourceIDs  []string
+		Redundant    bool
+		SLA          string
+		// Limiting is true for the components with the lowest SLA, which cap the SLA of the workload
+		Limiting bool
+		TenantID string
+	}
+
+	// workloadComponent - Resources in parallel, or a single resource, of a workload
+	workloadComponent struct {
+		resources      []*Resource
+		sla            float64
+		unavailability float64
+		known          bool
+	}
+)
+
+// slaNone is the SLA of the resources without a financially backed SLA. It is an unknown availability, not 0%
+const slaNone = "None"
+
+// LoadWorkloads loads the workload definitions of a YAML file
+func LoadWorkloads(file string) ([]*Workload, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading workloads: %s: %w", file, err)
+	}
+
+	workloads := []*Workload{}
+	if err := yaml.Unmarshal(content, &workloads); err != nil {
+		return nil, fmt.Errorf("failed parsing workloads: %s: %w", file, err)
+	}
+
+	for _, w := range workloads {
+		if err := w.compile(); err != nil {
+			return nil, fmt.Errorf("invalid workload in file: %s: %w", file, err)
+		}
+	}
+	return workloads, nil
+}
+
+// ComputeWorkloadSLAs returns the components of each workload, with their SLA and the composite SLA of the workload.
+// The SLAs of the resources are the results of the AZQR SLA rules. Resources without one are reported with an unknown SLA,
+// and the SLA of a workload with an unknown component is unknown, since it cannot be composed
+func ComputeWorkloadSLAs(workloads []*Workload, resources []*Resource, azqr []AzqrServiceResult) []WorkloadSLA {
+	slas := map[string]string{}
+	for _, a := range azqr {
+		for _, r := range a.Recommendations {
+			if r.RecommendationType == TypeSLA {
+				slas[a.ResourceID()] = r.Result
+			}
+		}
+	}
+
+	results := []WorkloadSLA{}
+	for _, w := range workloads {
+		components := w.components(resources, slas)
+
+		composite, known, lowest := 1.0, len(components) > 0, 1.0
+		for _, c := range components {
+			if !c.known {
+				known = false
+				continue
+			}
+			composite *= c.sla
+			lowest = math.Min(lowest, c.sla)
+		}
+
+		workloadSLA := "Unknown"
+		if known {
+			workloadSLA = formatSLA(composite)
+		}
+
+		for _, c := range components {
+			sla := "Unknown"
+			if c.known {
+				sla = formatSLA(c.sla)
+			}
+
+			ids, names, types := []string{}, []string{}, map[string]bool{}
+			for _, r := range c.resources {
+				ids = append(ids, r.ID)
+				names = append(names, r.Name)
+				types[r.Type] = true
+			}
+			resourceType := ""
+			if len(types) == 1 {
+				resourceType = c.resources[0].Type
+			}
+
+			results = append(results, WorkloadSLA{
+				Workload:     w.Name,
+				WorkloadSLA:  workloadSLA,
+				Component:    strings.Join(names, ", "),
+				ResourceType: resourceType,
+				ResourceIDs:  ids,
+				Redundant:    len(c.resources) > 1,
+				SLA:          sla,
+				Limiting:     c.known && c.sla == lowest,
+			})
+		}
+	}
+	return results
+}
+
+// components returns the components of the workload: a component per parallel group and per other resource
+func (w *Workload) components(resources []*Resource, slas map[string]string) []*workloadComponent {
+	groups := make([]*workloadComponent, len(w.parallel))
+	components := []*workloadComponent{}
+	for _, r := range resources {
+		if !w.contains(r) {
+			continue
+		}
+
+		sla, known := parseSLA(slas[strings.ToLower(r.ID)])
+		i := w.parallelGroup(r)
+		if i < 0 {
+			components = append(components, &workloadComponent{resources: []*Resource{r}, sla: sla, known: known})
+			continue
+		}
+
+		if groups[i] == nil {
+			groups[i] = &workloadComponent{unavailability: 1, known: true}
+			components = append(components, groups[i])
+		}
+		g := groups[i]
+		g.resources = append(g.resources, r)
+		// the group is unavailable when all its resources are unavailable. Its SLA is unknown when one of them is unknown
+		g.unavailability *= 1 - sla
+		g.known = g.known && known
+	}
+
+	for _, g := range groups {
+		if g != nil {
+			g.sla = 1 - g.unavailability
+		}
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i].resources[0].ID < components[j].resources[0].ID
+	})
+	return components
+}
+
+// parallelGroup returns the index of the parallel group of the resource, or -1
+func (w *Workload) parallelGroup(r *Resource) int {
+	for i, g := range w.parallel {
+		if matches(r.ID, g.ids, g.patterns) {
+			return i
+		}
+	}
+	return -1
+}
+
+// contains returns true when the resource belongs to the workload
+func (w *Workload) contains(r *Resource) bool {
+	if matches(r.ID, w.resources, w.resourcePatterns) {
+		return true
+	}
+	if (len(w.groups) > 0 || len(w.groupPatterns) > 0) && matches(GetResourceGroupIDFromResourceID(r.ID), w.groups, w.groupPatterns) {
+		return true
+	}
+	if len(w.Tags) == 0 {
+		return false
+	}
+	for k, v := range w.Tags {
+		if !hasTag(r.Tags, k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Workload) compile() error {
+	if w.Name == "" {
+		return fmt.Errorf("workload without name")
+	}
+	if len(w.Resources) == 0 && len(w.ResourceGroups) == 0 && len(w.Tags) == 0 {
+		return fmt.Errorf("workload %s requires resources, resourceGroups or tags", w.Name)
+	}
+
+	var err error
+	if w.resources, w.resourcePatterns, err = compileEntries(w.Resources); err != nil {
+		return fmt.Errorf("workload %s: %w", w.Name, err)
+	}
+	if w.groups, w.groupPatterns, err = compileEntries(w.ResourceGroups); err != nil {
+		return fmt.Errorf("workload %s: %w", w.Name, err)
+	}
+
+	w.parallel = []workloadGroup{}
+	for _, entries := range w.Parallel {
+		g := workloadGroup{}
+		if g.ids, g.patterns, err = compileEntries(entries); err != nil {
+			return fmt.Errorf("workload %s: %w", w.Name, err)
+		}
+		w.parallel = append(w.parallel, g)
+	}
+	return nil
+}
+
+// parseSLA parses a SLA like 99.95%. None is unknown: a resource without a financially backed SLA
+// has no guaranteed availability, which does not mean that it is never available
+func parseSLA(sla string) (float64, bool) {
+	if sla == slaNone {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(sla, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v / 100, true
+}
+
+// formatSLA formats a SLA as a percentage with up to 4 decimals, rounded down so redundancy never shows 100%
+func formatSLA(sla float64) string {
+	if sla == 0 {
+		return slaNone
+	}
+	// the epsilon absorbs the floating point error of values like 99.95
+	s := strconv.FormatFloat(math.Floor(sla*1e6+1e-6)/1e4, 'f', 4, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + "%"
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+)
+
+func TestComputeWorkloadSLAs(t *testing.T) {
+	content := `
+- name: shop
+  resourceGroups: [/subscriptions/0000/resourceGroups/rg-shop]
+  parallel:
+    - ["/subscriptions/0000/resourceGroups/rg-shop/providers/Microsoft.Web/sites/app-*"]
+- name: web
+  resources:
+    - /subscriptions/0000/resourceGroups/rg-shop/providers/Microsoft.Sql/servers/databases/db
+    - "/subscriptions/0000/resourceGroups/rg-shop/providers/Microsoft.Web/sites/app-*"
+  parallel:
+    - ["/subscriptions/0000/resourceGroups/rg-shop/providers/Microsoft.Web/sites/app-*"]
+- name: tagged
+  tags:
+    workload: tagged
+- name: edge
+  resources: ["regex:.*/Microsoft.Web/sites/app-(weu|eus)"]
+  parallel:
+    - ["regex:.*/Microsoft.Web/sites/app-.*"]
+`
+	workloads, err := LoadWorkloads(writeFile(t, "workloads.yaml", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource := func(rg, resourceType, name string, tags map[string]string) *Resource {
+		return &Resource{
+			ID:            "/subscriptions/0000/resourceGroups/" + rg + "/providers/" + resourceType + "/" + name,
+			ResourceGroup: rg,
+			Type:          resourceType,
+			Name:          name,
+			Tags:          tags,
+		}
+	}
+	resources := []*Resource{
+		resource("rg-shop", "Microsoft.Web/sites", "app-weu", nil),
+		resource("rg-shop", "Microsoft.Web/sites", "app-neu", nil),
+		resource("rg-shop", "Microsoft.Sql/servers/databases", "db", nil),
+		resource("rg-shop", "Microsoft.Network/publicIPAddresses", "pip", nil),
+		resource("rg-other", "Microsoft.Cache/redis", "redis", map[string]string{"Workload": "tagged"}),
+		resource("rg-other", "Microsoft.Storage/storageAccounts", "st", map[string]string{"workload": "tagged"}),
+		resource("rg-edge", "Microsoft.Web/sites", "app-eus", nil),
+	}
+	sla := func(r *Resource, value string) AzqrServiceResult {
+		return AzqrServiceResult{
+			SubscriptionID: "0000",
+			ResourceGroup:  r.ResourceGroup,
+			Type:           r.Type,
+			ServiceName:    r.Name,
+			Recommendations: map[string]AzqrResult{
+				"sla": {RecommendationType: TypeSLA, Result: value},
+			},
+		}
+	}
+	azqr := []AzqrServiceResult{
+		sla(resources[0], "99.95%"),
+		sla(resources[1], "99.95%"),
+		sla(resources[2], "99.99%"),
+		sla(resources[4], slaNone),
+	}
+
+	results := ComputeWorkloadSLAs(workloads, resources, azqr)
+	if len(results) != 8 {
+		t.Fatalf("expected 8 components, got %d: %v", len(results), results)
+	}
+
+	expected := []struct {
+		workload    string
+		workloadSLA string
+		component   string
+		redundant   bool
+		sla         string
+		limiting    bool
+	}{
+		// a component with an unknown SLA makes the SLA of the workload unknown
+		{"shop", "Unknown", "pip", false, "Unknown", false},
+		{"shop", "Unknown", "db", false, "99.99%", true},
+		{"shop", "Unknown", "app-weu, app-neu", true, "99.9999%", false},
+		// 99.99% * (1 - 0.0005^2)
+		{"web", "99.9899%", "db", false, "99.99%", true},
+		{"web", "99.9899%", "app-weu, app-neu", true, "99.9999%", false},
+		// None is not a SLA of 0%, but an unknown SLA
+		{"tagged", "Unknown", "redis", false, "Unknown", false},
+		{"tagged", "Unknown", "st", false, "Unknown", false},
+		// a parallel group with a resource of unknown SLA is unknown
+		{"edge", "Unknown", "app-weu, app-eus", true, "Unknown", false},
+	}
+	for i, e := range expected {
+		r := results[i]
+		if r.Workload != e.workload || r.WorkloadSLA != e.workloadSLA || r.Component != e.component ||
+			r.Redundant != e.redundant || r.SLA != e.sla || r.Limiting != e.limiting {
+			t.Errorf("component %d: expected %v, got %v", i, e, r)
+		}
+	}
+}
+
+func TestLoadWorkloads_Invalid(t *testing.T) {
+	tests := []string{
+		"- resources: [/subscriptions/0000]",
+		"- name: empty",
+		"- name: invalid\n  resources: [\"regex:(\"]",
+	}
+	for _, content := range tests {
+		if _, err := LoadWorkloads(writeFile(t, "workloads.yaml", content)); err == nil {
+			t.Errorf("expected an error for %q", content)
+		}
+	}
+}
@@ -83,8 +83,7 @@ func (a *WebPubSubScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armwebpubsub.ResourceInfo)
-				caf := strings.HasPrefix(*c.Name, "wps")
-				return !caf, ""
+				return scanners.CheckNamingConvention(scanContext, "Microsoft.SignalRService/webPubSub", "", *c.Name, "wps")
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
 		},
@@ -96,7 +95,7 @@ func (a *WebPubSubScanner) GetRecommendations() map[string]scanners.AzqrRecommen
 			Impact:           scanners.ImpactLow,
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armwebpubsub.ResourceInfo)
-				return len(c.Tags) == 0, ""
+				return scanners.CheckTags(scanContext, "Microsoft.SignalRService/webPubSub", c.ID, c.Tags)
 			},
 			LearnMoreUrl: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
@@ -0,0 +1,294 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package state saves the progress of a scan to a state file, so an interrupted scan can be resumed
+// with the results of the work that already completed.
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+// Version of the state file format
+const Version = 1
+
+// Phases saved in the state file, besides the APRL batches and the subscriptions
+const (
+	PhaseResources               = "resources"
+	PhaseDiagnostics             = "diagnostics"
+	PhaseResourceTypeCount       = "resourceTypeCount"
+	PhaseAdvisor                 = "advisor"
+	PhaseDefender                = "defender"
+	PhaseDefenderRecommendations = "defenderRecommendations"
+)
+
+type (
+	// State - Results of the completed work of a scan
+	State struct {
+		Version                 int                               `json:"version"`
+		Scope                   string                            `json:"scope"`
+		OutputFileName          string                            `json:"outputFileName"`
+		StartedAt               time.Time                         `json:"startedAt"`
+		Phases                  map[string]bool                   `json:"phases"`
+		AprlBatches             map[int][]scanners.AprlResult     `json:"aprlBatches"`
+		Subscriptions           map[string]*Subscription          `json:"subscriptions"`
+		Resources               []*scanners.Resource              `json:"resources"`
+		ExcludedResources       []*scanners.Resource              `json:"excludedResources"`
+		Diagnostics             map[string]bool                   `json:"diagnostics"`
+		ResourceTypeCount       []scanners.ResourceTypeCount      `json:"resourceTypeCount"`
+		Advisor                 []scanners.AdvisorResult          `json:"advisor"`
+		Defender                []scanners.DefenderResult         `json:"defender"`
+		DefenderRecommendations []scanners.DefenderRecommendation `json:"defenderRecommendations"`
+		// results of unchanged resources carried over from a previous scan by an incremental scan
+		CarriedAprl []scanners.AprlResult        `json:"carriedAprl"`
+		CarriedAzqr []scanners.AzqrServiceResult `json:"carriedAzqr"`
+	}
+
+	// Subscription - Results of a subscription scanned with the AZQR scanners
+	Subscription struct {
+		Azqr  []scanners.AzqrServiceResult `json:"azqr"`
+		Costs *scanners.CostResult         `json:"costs"`
+	}
+
+	// Checkpoint - Saves the state to a file every time it is updated. Safe for concurrent use.
+	// The first update writes the whole state and the next ones append their changes,
+	// so the cost of an update does not grow with the size of the state
+	Checkpoint struct {
+		mu    sync.Mutex
+		path  string
+		state *State
+		saved bool
+	}
+)
+
+// New creates an empty state for the given scope
+func New(scope, outputFileName string) *State {
+	return &State{
+		Version:        Version,
+		Scope:          scope,
+		OutputFileName: outputFileName,
+		StartedAt:      time.Now().UTC(),
+		Phases:         map[string]bool{},
+		AprlBatches:    map[int][]scanners.AprlResult{},
+		Subscriptions:  map[string]*Subscription{},
+	}
+}
+
+// Load reads a state file: the state followed by the changes appended by each update
+func Load(path string) (*State, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	s := &State{}
+	if err := decoder.Decode(s); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+
+	if s.Version != Version {
+		return nil, fmt.Errorf("state file %s has version %d, expected %d", path, s.Version, Version)
+	}
+
+	if s.Phases == nil {
+		s.Phases = map[string]bool{}
+	}
+	if s.AprlBatches == nil {
+		s.AprlBatches = map[int][]scanners.AprlResult{}
+	}
+	if s.Subscriptions == nil {
+		s.Subscriptions = map[string]*Subscription{}
+	}
+
+	for {
+		changes := &State{}
+		err := decoder.Decode(changes)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			// the last change was not fully written before the scan was interrupted. Its work is done again
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+		}
+		s.merge(changes)
+	}
+	return s, nil
+}
+
+// merge applies the changes saved by an update
+func (s *State) merge(changes *State) {
+	for k, v := range changes.Phases {
+		s.Phases[k] = v
+	}
+	for k, v := range changes.AprlBatches {
+		s.AprlBatches[k] = v
+	}
+	for k, v := range changes.Subscriptions {
+		s.Subscriptions[k] = v
+	}
+	if changes.Resources != nil {
+		s.Resources = changes.Resources
+	}
+	if changes.ExcludedResources != nil {
+		s.ExcludedResources = changes.ExcludedResources
+	}
+	if changes.Diagnostics != nil {
+		s.Diagnostics = changes.Diagnostics
+	}
+	if changes.ResourceTypeCount != nil {
+		s.ResourceTypeCount = changes.ResourceTypeCount
+	}
+	if changes.Advisor != nil {
+		s.Advisor = changes.Advisor
+	}
+	if changes.Defender != nil {
+		s.Defender = changes.Defender
+	}
+	if changes.DefenderRecommendations != nil {
+		s.DefenderRecommendations = changes.DefenderRecommendations
+	}
+	if changes.CarriedAprl != nil {
+		s.CarriedAprl = changes.CarriedAprl
+	}
+	if changes.CarriedAzqr != nil {
+		s.CarriedAzqr = changes.CarriedAzqr
+	}
+}
+
+// Clone returns a copy of the state. Results are shared, since they are never modified
+func (s *State) Clone() *State {
+	c := *s
+	c.Phases = make(map[string]bool, len(s.Phases))
+	for k, v := range s.Phases {
+		c.Phases[k] = v
+	}
+	c.AprlBatches = make(map[int][]scanners.AprlResult, len(s.AprlBatches))
+	for k, v := range s.AprlBatches {
+		c.AprlBatches[k] = v
+	}
+	c.Subscriptions = make(map[string]*Subscription, len(s.Subscriptions))
+	for k, v := range s.Subscriptions {
+		c.Subscriptions[k] = v
+	}
+	return &c
+}
+
+// AprlResults returns the APRL results of the state, including the results carried over from a previous scan
+func (s *State) AprlResults() []scanners.AprlResult {
+	batches := make([]int, 0, len(s.AprlBatches))
+	for i := range s.AprlBatches {
+		batches = append(batches, i)
+	}
+	sort.Ints(batches)
+
+	results := []scanners.AprlResult{}
+	for _, i := range batches {
+		results = append(results, s.AprlBatches[i]...)
+	}
+	return append(results, s.CarriedAprl...)
+}
+
+// AzqrResults returns the AZQR results of the state, including the results carried over from a previous scan
+func (s *State) AzqrResults() []scanners.AzqrServiceResult {
+	subscriptions := make([]string, 0, len(s.Subscriptions))
+	for id := range s.Subscriptions {
+		subscriptions = append(subscriptions, id)
+	}
+	sort.Strings(subscriptions)
+
+	results := []scanners.AzqrServiceResult{}
+	for _, id := range subscriptions {
+		results = append(results, s.Subscriptions[id].Azqr...)
+	}
+	return append(results, s.CarriedAzqr...)
+}
+
+// NewCheckpoint creates a Checkpoint that saves the state to path. An empty path only keeps the state in memory
+func NewCheckpoint(path string, state *State) *Checkpoint {
+	return &Checkpoint{
+		path:  path,
+		state: state,
+	}
+}
+
+// Update applies update to the state and saves it. update is also applied to an empty state to get the changes
+// to append, so it must only assign the results of the completed work
+func (c *Checkpoint) Update(update func(s *State)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	update(c.state)
+
+	if c.path == "" {
+		return nil
+	}
+
+	if !c.saved {
+		if err := c.save(); err != nil {
+			return err
+		}
+		c.saved = true
+		return nil
+	}
+
+	changes := &State{
+		Phases:        map[string]bool{},
+		AprlBatches:   map[int][]scanners.AprlResult{},
+		Subscriptions: map[string]*Subscription{},
+	}
+	update(changes)
+	return c.append(changes)
+}
+
+// save writes the whole state
+func (c *Checkpoint) save() error {
+	content, err := json.Marshal(c.state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	// write to a temporary file first, so an interrupted write never corrupts the previous state
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(content, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// append appends the changes of an update to the state file
+func (c *Checkpoint) append(changes *State) error {
+	content, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", c.path, err)
+	}
+	if _, err := f.Write(append(content, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write state file %s: %w", c.path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", c.path, err)
+	}
+	return nil
+}
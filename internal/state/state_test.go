@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/scanners"
+)
+
+func TestCheckpoint_UpdateAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state.json")
+	cp := NewCheckpoint(path, New("scope", "scan"))
+
+	aprl := []scanners.AprlResult{{RecommendationID: "r1", ResourceID: "/subscriptions/s/resourceGroups/rg/providers/a/b/c"}}
+	sub := &Subscription{Azqr: []scanners.AzqrServiceResult{{SubscriptionID: "s", ServiceName: "c"}}}
+
+	if err := cp.Update(func(s *State) { s.AprlBatches[3] = aprl }); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.Update(func(s *State) {
+		s.Subscriptions["s"] = sub
+		s.Phases[PhaseAdvisor] = true
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary file to be renamed")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(content), "\n"); lines != 2 {
+		t.Errorf("expected the state and the appended changes, got %d lines", lines)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Scope != "scope" || loaded.OutputFileName != "scan" {
+		t.Errorf("unexpected scope or output file name: %s, %s", loaded.Scope, loaded.OutputFileName)
+	}
+	if !reflect.DeepEqual(loaded.AprlBatches[3], aprl) {
+		t.Errorf("expected APRL batch %v, got %v", aprl, loaded.AprlBatches[3])
+	}
+	if !reflect.DeepEqual(loaded.Subscriptions["s"], sub) {
+		t.Errorf("expected subscription %v, got %v", sub, loaded.Subscriptions["s"])
+	}
+	if !loaded.Phases[PhaseAdvisor] || loaded.Phases[PhaseDefender] {
+		t.Errorf("unexpected phases: %v", loaded.Phases)
+	}
+}
+
+func TestLoad_TruncatedChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state.json")
+	content := `{"version": 1, "scope": "scope", "phases": {"resources": true}}
+{"phases": {"advisor": true}}
+{"phases": {"defender": tr`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Phases[PhaseResources] || !loaded.Phases[PhaseAdvisor] || loaded.Phases[PhaseDefender] {
+		t.Errorf("unexpected phases: %v", loaded.Phases)
+	}
+}
+
+func TestLoad_VersionMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := New("scope", "scan")
+	s.Phases[PhaseResources] = true
+
+	c := s.Clone()
+	c.Phases[PhaseDiagnostics] = true
+	c.Subscriptions["s"] = &Subscription{}
+
+	if s.Phases[PhaseDiagnostics] || len(s.Subscriptions) != 0 {
+		t.Error("expected the clone to be independent of the original state")
+	}
+	if !c.Phases[PhaseResources] {
+		t.Error("expected the clone to keep the completed phases")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azqr/internal/cassette"
+	"github.com/Azure/azqr/internal/clouds"
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/rs/zerolog/log"
+)
+
+// LoadTenants reads a tenants file with one tenant id per line. Empty lines and lines starting with # are ignored
+func LoadTenants(tenantsFile string) ([]string, error) {
+	f, err := os.Open(tenantsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file %s: %w", tenantsFile, err)
+	}
+	defer f.Close()
+
+	tenants := []string{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tenants = append(tenants, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tenants file %s: %w", tenantsFile, err)
+	}
+	return tenants, nil
+}
+
+// scanTenants scans each tenant with its own credential and merges the results.
+// All the credentials are validated before scanning, and a tenant that fails is reported as a scan error.
+func (sc Scanner) scanTenants(ctx context.Context, params *ScanParams, rules *scanners.Rules) (*renderers.ReportData, error) {
+	if params.Credential != nil {
+		return nil, errors.New("a credential cannot be used to scan multiple tenants")
+	}
+	if params.ManagementGroupID != "" || params.SubscriptionID != "" || params.ResourceGroup != "" {
+		return nil, errors.New("management group, subscription and resource group cannot be used to scan multiple tenants")
+	}
+	if params.Checkpoint || params.ResumeFile != "" {
+		return nil, errors.New("checkpoint and resume are not supported when scanning multiple tenants")
+	}
+
+	azureCloud, err := clouds.Get(params.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := []string{}
+	seen := map[string]bool{}
+	for _, t := range params.TenantIDs {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tenants = append(tenants, t)
+	}
+
+	// create the credential of each tenant, so authentication errors are reported before scanning
+	creds := make([]azcore.TokenCredential, len(tenants))
+	for i, t := range tenants {
+		if params.ReplayDir != "" {
+			creds[i] = cassette.Credential{}
+			continue
+		}
+
+		tenantParams := *params
+		tenantParams.TenantID = t
+		cred, err := sc.newAzureCredential(ctx, &tenantParams, azureCloud)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", t, err)
+		}
+		if err := sc.validateCredential(ctx, cred, azureCloud); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", t, err)
+		}
+		creds[i] = cred
+	}
+
+	outputFile := sc.generateOutputFileName(params.OutputName)
+	reportData := renderers.NewReportData(outputFile, params.Mask)
+	reportData.Remediation = params.Remediation
+	reportData.Tenants = tenants
+
+	for i, t := range tenants {
+		log.Info().Msgf("Scanning tenant %s", t)
+
+		tenantParams := *params
+		tenantParams.TenantIDs = nil
+		tenantParams.TenantID = t
+		tenantParams.Credential = creds[i]
+		// each tenant excludes its own resources by their tags
+		tenantParams.Filters = params.Filters.Clone()
+		tenantParams.OutputName = outputFile
+		if params.RecordDir != "" {
+			tenantParams.RecordDir = filepath.Join(params.RecordDir, t)
+		}
+		if params.ReplayDir != "" {
+			tenantParams.ReplayDir = filepath.Join(params.ReplayDir, t)
+		}
+
+		data, err := sc.scanTenant(ctx, &tenantParams, rules)
+		if params.Filters != nil {
+			params.Filters.Azqr.AddMatchedPatterns(tenantParams.Filters.Azqr)
+		}
+		if err != nil {
+			scanError := scanners.NewScanError(scanners.PhaseTenant, "", "", t, err)
+			scanError.TenantID = t
+			reportData.Errors = append(reportData.Errors, scanError)
+			continue
+		}
+
+		data.SetTenant(t)
+		reportData.Merge(data)
+	}
+
+	return &reportData, nil
+}
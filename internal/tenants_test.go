@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azqr/internal/cassette"
+)
+
+func TestLoadTenants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.txt")
+	content := "# customers\n11111111-1111-1111-1111-111111111111\n\n  22222222-2222-2222-2222-222222222222  \n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tenants, err := LoadTenants(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"}
+	if !reflect.DeepEqual(tenants, want) {
+		t.Errorf("expected %v, got %v", want, tenants)
+	}
+}
+
+func TestScanTenants_InvalidParams(t *testing.T) {
+	tests := map[string]ScanParams{
+		"credential":   {TenantIDs: []string{"t1"}, Credential: cassette.Credential{}},
+		"subscription": {TenantIDs: []string{"t1"}, SubscriptionID: "00000000-0000-0000-0000-000000000000"},
+		"resume":       {TenantIDs: []string{"t1"}, ResumeFile: "scan.state.json"},
+		"checkpoint":   {TenantIDs: []string{"t1"}, Checkpoint: true},
+	}
+	for name, params := range tests {
+		if _, err := (Scanner{}).Scan(context.Background(), &params); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
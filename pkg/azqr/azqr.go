@@ -0,0 +1,266 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package azqr exposes Azure Quick Review (azqr) as a Go library.
+//
+// Scan runs the same scan as the azqr CLI and returns the results as a ReportData value.
+// Reports are only written to disk when a renderer is enabled in the Options.
+package azqr
+
+import (
+	"context"
+
+	"github.com/Azure/azqr/internal"
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+	_ "github.com/Azure/azqr/internal/scanners/registry"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+type (
+	// ReportData - Results of a scan
+	ReportData = renderers.ReportData
+	// Filters - Include and exclude filters
+	Filters = scanners.Filters
+	// AzqrServiceResult - Result of the AZQR recommendations evaluated against a resource
+	AzqrServiceResult = scanners.AzqrServiceResult
+	// AzqrResult - Result of a single AZQR recommendation
+	AzqrResult = scanners.AzqrResult
+	// AprlRecommendation - APRL, AOR or AZQR recommendation
+	AprlRecommendation = scanners.AprlRecommendation
+	// AprlResult - Resource impacted by an APRL or AOR recommendation
+	AprlResult = scanners.AprlResult
+	// AdvisorResult - Azure Advisor recommendation
+	AdvisorResult = scanners.AdvisorResult
+	// DefenderResult - Microsoft Defender for Cloud plan status
+	DefenderResult = scanners.DefenderResult
+	// DefenderRecommendation - Microsoft Defender for Cloud recommendation
+	DefenderRecommendation = scanners.DefenderRecommendation
+	// CostResult - Costs of the scanned subscriptions
+	CostResult = scanners.CostResult
+	// ScanError - Error raised while scanning. The scan continues and the errors are listed in ReportData.Errors
+	ScanError = scanners.ScanError
+	// WorkloadSLA - Component of a workload with its SLA and the composite SLA of the workload
+	WorkloadSLA = scanners.WorkloadSLA
+	// Finding - Resource impacted by a recommendation
+	Finding = renderers.Finding
+	// Remediation - Templates of the fix of a recommendation
+	Remediation = scanners.Remediation
+	// SuppressedResult - Finding suppressed by a suppression of the filters
+	SuppressedResult = scanners.SuppressedResult
+	// Resource - Resource found in the scanned subscriptions
+	Resource = scanners.Resource
+
+	// Options - Options for a scan
+	Options struct {
+		// ManagementGroupID scans all subscriptions under the management group
+		ManagementGroupID string
+		// SubscriptionID scans a single subscription
+		SubscriptionID string
+		// ResourceGroup scans a single resource group. Requires SubscriptionID
+		ResourceGroup string
+		// ScannerKeys are the abbreviations of the services to scan (e.g. "st", "vm"). Empty scans all services
+		ScannerKeys []string
+		// FiltersFile is the path to a filters file (YAML format). Ignored when Filters is set
+		FiltersFile string
+		// Filters are the include and exclude filters. Use LoadFilters to create them
+		Filters *Filters
+		// Credential is used to authenticate. When nil DefaultAzureCredential is used
+		Credential azcore.TokenCredential
+		// ForceAzureCliCredential uses the Azure CLI credential when Credential is nil. Same as Auth "cli"
+		ForceAzureCliCredential bool
+		// Auth is the authentication mode used when Credential is nil: default, cli, managed-identity,
+		// workload-identity, client-certificate or device-code
+		Auth string
+		// ClientID is the client id of the managed identity, workload identity, service principal or device code application
+		ClientID string
+		// TenantID is the tenant to authenticate with
+		TenantID string
+		// TenantIDs scans several tenants, each with its own credential, and merges the results.
+		// A Tenant column is added to the reports
+		TenantIDs []string
+		// ClientCertificate is the path of the certificate used by the client-certificate mode
+		ClientCertificate string
+		// Defender scans the Microsoft Defender for Cloud status and recommendations
+		Defender bool
+		// Advisor scans the Azure Advisor recommendations
+		Advisor bool
+		// Cost scans the costs of the subscriptions
+		Cost bool
+		// AzqrRecommendations evaluates the AZQR recommendations
+		AzqrRecommendations bool
+		// Mask masks the subscription ids in the rendered reports
+		Mask bool
+		// OutputName is the output file name without extension used by the renderers
+		OutputName string
+		// Xlsx renders the Excel report
+		Xlsx bool
+		// Json renders the json reports
+		Json bool
+		// Html renders the self-contained html report
+		Html bool
+		// Sarif renders the SARIF report
+		Sarif bool
+		// Junit renders the JUnit XML report
+		Junit bool
+		// Markdown renders the markdown summary
+		Markdown bool
+		// BaselineFile is the findings file (<OutputName>.findings.json) written by the json report of a previous scan.
+		// Only the findings missing from it are new
+		BaselineFile string
+		// Csv renders the csv reports
+		Csv bool
+		// Parallelism is the number of subscriptions scanned concurrently
+		Parallelism int
+		// MaxConcurrentRequests limits the number of in-flight ARM requests across all subscriptions. Zero means no limit
+		MaxConcurrentRequests int
+		// RecordDir records the ARM and Resource Graph traffic to a cassette directory
+		RecordDir string
+		// ReplayDir replays the ARM and Resource Graph traffic from a cassette directory, without reaching Azure
+		ReplayDir string
+		// Checkpoint saves the scan progress to <OutputName>.state.json after each phase, to resume an interrupted scan
+		Checkpoint bool
+		// Cloud is the Azure cloud to scan: AzureCloud (default), AzureUSGovernment or AzureChinaCloud
+		Cloud string
+		// ResumeFile resumes an interrupted scan from its state file, skipping the work that already completed
+		ResumeFile string
+		// Since scans only the resources changed since a previous scan, given its state file.
+		// The results of the unchanged resources are carried over from the previous scan
+		Since string
+		// AzqrRulesDirs are directories of declarative AZQR rules, evaluated with the built-in rules
+		AzqrRulesDirs []string
+		// RulesDirs are directories of custom Resource Graph recommendations in the APRL format, given as <dir> or <source>=<dir>
+		RulesDirs []string
+		// PolicyDir is a directory of Rego policies. Their deny rules are evaluated against the ARM JSON of the resources
+		PolicyDir string
+		// NamingPolicyFile is a YAML file with the naming conventions per resource type, used instead of the CAF prefixes
+		NamingPolicyFile string
+		// TagPolicyFile is a YAML file with the required tags and their allowed values, used instead of checking that resources have tags
+		TagPolicyFile string
+		// WorkloadsFile is a YAML file with workload definitions. Their composite SLAs are returned in ReportData.WorkloadSLAs
+		WorkloadsFile string
+		// Remediation adds the fixes of the findings to the impacted resources and renders a remediation script per subscription
+		Remediation bool
+	}
+)
+
+// DefaultOptions returns the options used by the azqr scan command, with all renderers disabled
+func DefaultOptions() *Options {
+	return &Options{
+		Defender:              true,
+		Advisor:               true,
+		Cost:                  true,
+		AzqrRecommendations:   true,
+		Mask:                  true,
+		Parallelism:           1,
+		MaxConcurrentRequests: 100,
+	}
+}
+
+// ScannerKeys returns the abbreviations of all the available service scanners
+func ScannerKeys() []string {
+	keys, _ := scanners.GetScanners()
+	return keys
+}
+
+// LoadFilters loads a filters file (YAML format). An empty file name returns the default filters
+func LoadFilters(filtersFile string, scannerKeys []string) (*Filters, error) {
+	if len(scannerKeys) == 0 {
+		scannerKeys = ScannerKeys()
+	}
+	return scanners.LoadFilters(filtersFile, scannerKeys)
+}
+
+// QualityGate returns the quality gate rules broken by the new findings of a scan.
+// Rules are like impact=High, category=Security>5 or count>100
+func QualityGate(data *ReportData, failOn []string) ([]string, error) {
+	rules, err := internal.ParseFailOnRules(failOn)
+	if err != nil {
+		return nil, err
+	}
+	return internal.EvaluateQualityGate(rules, data.NewFindings()), nil
+}
+
+// LoadTenants loads a tenants file with one tenant id per line
+func LoadTenants(tenantsFile string) ([]string, error) {
+	return internal.LoadTenants(tenantsFile)
+}
+
+// Scan scans the Azure resources in scope and returns the results.
+// Reports are rendered only for the renderers enabled in the options.
+func Scan(ctx context.Context, options *Options) (*ReportData, error) {
+	if options == nil {
+		options = DefaultOptions()
+	}
+
+	scannerKeys := options.ScannerKeys
+	if len(scannerKeys) == 0 {
+		scannerKeys = ScannerKeys()
+	}
+
+	// the scan adds the subscription, resource group and excluded resources to its own copy of the filters
+	filters := options.Filters.Clone()
+	if filters == nil {
+		var err error
+		filters, err = LoadFilters(options.FiltersFile, scannerKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	params := &internal.ScanParams{
+		ManagementGroupID:       options.ManagementGroupID,
+		SubscriptionID:          options.SubscriptionID,
+		ResourceGroup:           options.ResourceGroup,
+		OutputName:              options.OutputName,
+		Defender:                options.Defender,
+		Advisor:                 options.Advisor,
+		Cost:                    options.Cost,
+		Mask:                    options.Mask,
+		Xlsx:                    options.Xlsx,
+		Csv:                     options.Csv,
+		Json:                    options.Json,
+		Html:                    options.Html,
+		Sarif:                   options.Sarif,
+		Junit:                   options.Junit,
+		Markdown:                options.Markdown,
+		Baseline:                options.BaselineFile,
+		ScannerKeys:             scannerKeys,
+		ForceAzureCliCredential: options.ForceAzureCliCredential,
+		Credential:              options.Credential,
+		Auth:                    options.Auth,
+		ClientID:                options.ClientID,
+		TenantID:                options.TenantID,
+		ClientCertificate:       options.ClientCertificate,
+		TenantIDs:               options.TenantIDs,
+		Filters:                 filters,
+		UseAzqrRecommendations:  options.AzqrRecommendations,
+		Parallelism:             options.Parallelism,
+		MaxConcurrentRequests:   options.MaxConcurrentRequests,
+		RecordDir:               options.RecordDir,
+		ReplayDir:               options.ReplayDir,
+		Checkpoint:              options.Checkpoint,
+		ResumeFile:              options.ResumeFile,
+		Since:                   options.Since,
+		AzqrRulesDirs:           options.AzqrRulesDirs,
+		RulesDirs:               options.RulesDirs,
+		PolicyDir:               options.PolicyDir,
+		NamingPolicy:            options.NamingPolicyFile,
+		TagPolicy:               options.TagPolicyFile,
+		WorkloadsFile:           options.WorkloadsFile,
+		Remediation:             options.Remediation,
+		Cloud:                   options.Cloud,
+	}
+
+	scanner := internal.Scanner{}
+	data, err := scanner.Scan(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scanner.Render(data, params); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScan_InvalidScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *Options
+	}{
+		{
+			name: "Management Group with Subscription",
+			options: &Options{
+				ManagementGroupID: "mg",
+				SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+			},
+		},
+		{
+			name: "Resource Group without Subscription",
+			options: &Options{
+				ResourceGroup: "rg",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Scan(context.Background(), tt.options)
+			if err == nil {
+				t.Errorf("Scan() error = nil, want error")
+			}
+			if data != nil {
+				t.Errorf("Scan() data = %v, want nil", data)
+			}
+		})
+	}
+}
+
+func TestScannerKeys(t *testing.T) {
+	keys := ScannerKeys()
+	if len(keys) == 0 {
+		t.Fatal("ScannerKeys() returned no scanners")
+	}
+
+	filters, err := LoadFilters("", []string{"st"})
+	if err != nil {
+		t.Fatalf("LoadFilters() error = %v", err)
+	}
+	if len(filters.Azqr.Scanners) != 1 {
+		t.Errorf("LoadFilters() scanners = %d, want 1", len(filters.Azqr.Scanners))
+	}
+}